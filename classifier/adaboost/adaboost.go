@@ -0,0 +1,370 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package adaboost implement the AdaBoost.M1 algorithm by Freund and Schapire,
+using depth-limited CART as weak learner.
+
+	Freund, Yoav, and Robert E. Schapire. "Experiments with a new boosting
+	algorithm." ICML. Vol. 96. 1996.
+*/
+package adaboost
+
+import (
+	"errors"
+	"fmt"
+	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/go-mining/classifier/cart"
+	"github.com/shuLhan/numerus"
+	"github.com/shuLhan/tabula"
+	"math"
+	"os"
+	"strconv"
+)
+
+const (
+	tag = "[adaboost]"
+
+	// DefNumRound default number of boosting round.
+	DefNumRound = 50
+
+	// DefMaxDepth default depth of weak learner, which is a stump.
+	DefMaxDepth = 1
+
+	// DefOOBStatsFile default statistic file output for each round.
+	DefOOBStatsFile = "adaboost.oob.stat"
+
+	// DefPerfFile default performance file output.
+	DefPerfFile = "adaboost.perf"
+
+	// DefStatFile default statistic file.
+	DefStatFile = "adaboost.stat"
+
+	// minError is the error value used to compute the alpha of perfect
+	// learner, to prevent division by zero.
+	minError = 1e-10
+)
+
+var (
+	// DEBUG level, can be set from environment "ADABOOST_DEBUG".
+	DEBUG = 0
+)
+
+var (
+	// ErrNoInput will tell you when no input is given.
+	ErrNoInput = errors.New("adaboost: input samples is empty")
+)
+
+/*
+Runtime contains input and output configuration when generating AdaBoost
+classifier.
+*/
+type Runtime struct {
+	// Runtime embed common fields for classifier.
+	classifier.Runtime
+
+	// NRound maximum number of boosting round or number of weak learner.
+	NRound int `json:"NRound"`
+	// MaxDepth maximum depth of each weak learner.
+	MaxDepth int `json:"MaxDepth"`
+
+	// learners contain all weak learner.
+	learners []cart.Runtime
+	// alphas contain weight of each weak learner when voting.
+	alphas []float64
+	// weights contain the weight of each training samples.
+	weights []float64
+}
+
+func init() {
+	var e error
+	DEBUG, e = strconv.Atoi(os.Getenv("ADABOOST_DEBUG"))
+	if e != nil {
+		DEBUG = 0
+	}
+}
+
+//
+// Learners return all weak learners.
+//
+func (ada *Runtime) Learners() []cart.Runtime {
+	return ada.learners
+}
+
+//
+// Alphas return weight of each weak learner.
+//
+func (ada *Runtime) Alphas() []float64 {
+	return ada.alphas
+}
+
+//
+// Initialize will check inputs and set it to default values if invalid.
+// It will also set the weight of each samples to 1/n.
+//
+func (ada *Runtime) Initialize(samples tabula.ClasetInterface) error {
+	if ada.NRound <= 0 {
+		ada.NRound = DefNumRound
+	}
+	if ada.MaxDepth <= 0 {
+		ada.MaxDepth = DefMaxDepth
+	}
+	if ada.OOBStatsFile == "" {
+		ada.OOBStatsFile = DefOOBStatsFile
+	}
+	if ada.PerfFile == "" {
+		ada.PerfFile = DefPerfFile
+	}
+	if ada.StatFile == "" {
+		ada.StatFile = DefStatFile
+	}
+
+	nrow := samples.GetNRow()
+	ada.learners = nil
+	ada.alphas = nil
+	ada.weights = make([]float64, nrow)
+	for x := range ada.weights {
+		ada.weights[x] = 1 / float64(nrow)
+	}
+
+	return ada.Runtime.Initialize()
+}
+
+//
+// Build will train the weak learners using samples.
+//
+// Algorithm,
+//
+// (0) Check input and initialize samples weight.
+// (1) For 0 to NRound,
+// (1.1) train a weak learner using weighted samples,
+// (1.2) stop if weighted error is greater or equal to 0.5.
+// (2) Compute and write total statistic.
+//
+func (ada *Runtime) Build(samples tabula.ClasetInterface) (e error) {
+	if samples == nil || samples.GetNRow() <= 0 {
+		return ErrNoInput
+	}
+
+	// (0)
+	e = ada.Initialize(samples)
+	if e != nil {
+		return
+	}
+
+	fmt.Println(tag, "Training set    :", samples)
+	fmt.Println(tag, "Config          :", ada)
+
+	// (1)
+	for t := 0; t < ada.NRound; t++ {
+		if DEBUG >= 1 {
+			fmt.Println(tag, "round #", t)
+		}
+
+		// (1.1)
+		errv, e := ada.Boost(samples)
+		if e != nil {
+			return e
+		}
+
+		// (1.2)
+		if errv >= 0.5 || errv == 0 {
+			break
+		}
+	}
+
+	// (2)
+	return ada.Finalize()
+}
+
+//
+// Boost will train one weak learner using current samples weight, compute
+// their alpha, and update the samples weight. It will return the weighted
+// error of the learner.
+//
+// Algorithm,
+//
+// (1) Build CART with limited depth using samples weight.
+// (2) Classify each samples and compute the weighted error.
+// (3) If error is greater or equal to 0.5, the learner is discarded.
+// (4) Compute learner weight, alpha = log((1 - error) / error).
+// (5) Decrease the weight of correctly classified samples by multiplying
+//     it with error / (1 - error), and normalize the weights.
+// (6) Compute statistic of current ensemble on training samples.
+//
+func (ada *Runtime) Boost(samples tabula.ClasetInterface) (
+	errv float64, e error,
+) {
+	stat := &classifier.Stat{}
+	stat.ID = int64(len(ada.learners))
+	stat.Start()
+
+	// (1)
+	learner := cart.Runtime{
+		SplitMethod: cart.SplitMethodGini,
+		MaxDepth:    ada.MaxDepth,
+		Weights:     ada.weights,
+	}
+
+	// CART will sort the samples in place, we build it on the copy to
+	// keep the samples weight aligned with the original samples.
+	trainset := copySamples(samples)
+
+	e = learner.Build(trainset)
+	if e != nil {
+		return 1, e
+	}
+	learner.Weights = nil
+
+	// (2)
+	actuals := samples.GetClassAsStrings()
+	rows := samples.GetRows()
+	miss := make([]bool, len(*rows))
+
+	sumw := numerus.Floats64Sum(ada.weights)
+	for x, row := range *rows {
+		if learner.Classify(row) != actuals[x] {
+			miss[x] = true
+			errv += ada.weights[x]
+		}
+	}
+	errv = errv / sumw
+
+	if DEBUG >= 1 {
+		fmt.Println(tag, "weighted error:", errv)
+	}
+
+	// (3)
+	if errv >= 0.5 {
+		return errv, nil
+	}
+
+	// (4)
+	beta := math.Max(errv, minError) / (1 - errv)
+	alpha := math.Log(1 / beta)
+
+	ada.learners = append(ada.learners, learner)
+	ada.alphas = append(ada.alphas, alpha)
+
+	// (5)
+	if errv > 0 {
+		sumw = 0
+		for x := range ada.weights {
+			if !miss[x] {
+				ada.weights[x] *= beta
+			}
+			sumw += ada.weights[x]
+		}
+		for x := range ada.weights {
+			ada.weights[x] /= sumw
+		}
+	}
+
+	// (6)
+	vs := samples.GetClassValueSpace()
+	predicts := make([]string, len(*rows))
+	for x, row := range *rows {
+		predicts[x], _ = ada.Classify(row, vs)
+	}
+
+	cm := ada.ComputeCM(nil, vs, actuals, predicts)
+	ada.AddOOBCM(cm)
+
+	stat.End()
+	ada.AddStat(stat)
+	ada.ComputeStatFromCM(stat, cm)
+	ada.ComputeStatTotal(stat)
+
+	e = ada.WriteOOBStat(stat)
+
+	return errv, e
+}
+
+//
+// Classify will return the class of `sample` by combining the vote of each
+// weak learner weighted by their alpha, and the score of each class in value
+// space `vs`, normalized by sum of all alphas.
+//
+func (ada *Runtime) Classify(sample *tabula.Row, vs []string) (
+	class string, scores []float64,
+) {
+	scores = make([]float64, len(vs))
+
+	for x, learner := range ada.learners {
+		vote := learner.Classify(sample)
+
+		for y, v := range vs {
+			if vote == v {
+				scores[y] += ada.alphas[x]
+				break
+			}
+		}
+	}
+
+	sumAlpha := numerus.Floats64Sum(ada.alphas)
+	if sumAlpha > 0 {
+		for x := range scores {
+			scores[x] /= sumAlpha
+		}
+	}
+
+	_, maxIdx, ok := numerus.Floats64FindMax(scores)
+	if ok {
+		class = vs[maxIdx]
+	}
+
+	return class, scores
+}
+
+//
+// ClassifySet given a samples predict their class by weighted vote of all
+// weak learners, and return their class prediction, confusion matrix, and
+// score of the first class in value space for each sample.
+//
+func (ada *Runtime) ClassifySet(samples tabula.ClasetInterface,
+	sampleIds []int,
+) (
+	predicts []string, cm *classifier.CM, probs []float64,
+) {
+	stat := classifier.Stat{}
+	stat.Start()
+
+	vs := samples.GetClassValueSpace()
+	actuals := samples.GetClassAsStrings()
+
+	rows := samples.GetRows()
+	for _, row := range *rows {
+		class, scores := ada.Classify(row, vs)
+
+		predicts = append(predicts, class)
+		probs = append(probs, scores[0])
+	}
+
+	cm = ada.ComputeCM(sampleIds, vs, actuals, predicts)
+
+	ada.ComputeStatFromCM(&stat, cm)
+	stat.End()
+
+	if len(sampleIds) <= 0 {
+		fmt.Println(tag, "CM:", cm)
+		fmt.Println(tag, "Classifying stat:", stat)
+		_ = stat.Write(ada.StatFile)
+	}
+
+	return predicts, cm, probs
+}
+
+//
+// copySamples return a new dataset that contain copy of all rows in
+// `samples`.
+//
+func copySamples(samples tabula.ClasetInterface) tabula.ClasetInterface {
+	clone := samples.Clone().(tabula.ClasetInterface)
+
+	for _, row := range *samples.GetRows() {
+		clone.PushRow(row.Clone())
+	}
+
+	return clone
+}
@@ -0,0 +1,105 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package adaboost_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/go-mining/classifier/adaboost"
+	"github.com/shuLhan/go-mining/classifier/cart"
+	"github.com/shuLhan/tabula"
+	"testing"
+)
+
+const (
+	SampleFile = "../../testdata/phoneme/phoneme.dsv"
+)
+
+func accuracy(actuals, predicts []string) float64 {
+	n := 0
+	for x := range actuals {
+		if actuals[x] == predicts[x] {
+			n++
+		}
+	}
+	return float64(n) / float64(len(actuals))
+}
+
+func TestAdaBoostPhoneme(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead(SampleFile, &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	nbag := (samples.Len() * 63) / 100
+	train, test, _, _ := tabula.RandomPickRows(&samples, nbag, false)
+
+	trainset := train.(tabula.ClasetInterface)
+	testset := test.(tabula.ClasetInterface)
+
+	trainset.SetClassIndex(samples.GetClassIndex())
+	testset.SetClassIndex(samples.GetClassIndex())
+
+	actuals := testset.GetClassAsStrings()
+
+	// Build single CART with the same depth as weak learner.
+	stump := cart.Runtime{
+		SplitMethod: cart.SplitMethodGini,
+		MaxDepth:    adaboost.DefMaxDepth,
+	}
+
+	// CART sort the samples in place, build it on a copy so the order
+	// of samples for AdaBoost is not changed.
+	stumpset := trainset.Clone().(tabula.ClasetInterface)
+	for _, row := range *trainset.GetRows() {
+		stumpset.PushRow(row.Clone())
+	}
+
+	e = stump.Build(stumpset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	var stumpPredicts []string
+	for _, row := range *testset.GetRows() {
+		stumpPredicts = append(stumpPredicts, stump.Classify(row))
+	}
+
+	stumpAcc := accuracy(actuals, stumpPredicts)
+
+	// Build AdaBoost.
+	ada := adaboost.Runtime{
+		Runtime: classifier.Runtime{
+			OOBStatsFile: "phoneme.adaboost.oob",
+			StatFile:     "phoneme.adaboost.stat",
+			PerfFile:     "phoneme.adaboost.perf",
+		},
+		NRound: 20,
+	}
+
+	e = ada.Build(trainset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	predicts, cm, _ := ada.ClassifySet(testset, nil)
+
+	adaAcc := accuracy(actuals, predicts)
+
+	fmt.Println("[adaboost_test] CM:", cm)
+	fmt.Println("[adaboost_test] # learners:", len(ada.Learners()))
+	fmt.Printf("[adaboost_test] accuracy single CART: %.4f,"+
+		" AdaBoost: %.4f\n", stumpAcc, adaAcc)
+
+	if len(ada.Learners()) != len(ada.Alphas()) {
+		t.Fatal("Expecting one alpha for each learner")
+	}
+	if adaAcc < stumpAcc {
+		t.Fatalf("Expecting AdaBoost accuracy %f >= single CART %f",
+			adaAcc, stumpAcc)
+	}
+}
@@ -17,12 +17,15 @@ The implementation is based on Data Mining book,
 package cart
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"github.com/shuLhan/go-mining/gain/gini"
 	"github.com/shuLhan/go-mining/tree/binary"
 	"github.com/shuLhan/numerus"
 	"github.com/shuLhan/tabula"
 	"github.com/shuLhan/tekstus"
+	"math/rand"
 	"os"
 	"strconv"
 )
@@ -33,6 +36,28 @@ const (
 	//
 	// This option is used in Runtime.SplitMethod.
 	SplitMethodGini = "gini"
+
+	// SplitMethodChiSquare if defined in Runtime, the dataset will be
+	// splitted using the chi-squared statistic between each possible
+	// partition and the class distribution, selecting the partition with
+	// the largest statistic.
+	// If Weights or ClassWeights is set, the weighted Gini gain is used
+	// instead.
+	//
+	// This option is used in Runtime.SplitMethod.
+	SplitMethodChiSquare = "chisquare"
+
+	// SplitMethodHellinger if defined in Runtime, the dataset will be
+	// splitted using the Hellinger distance between the distribution of
+	// positive class (the first class in value space) and the rest of
+	// classes on each possible partition, selecting the partition with
+	// the largest distance. Hellinger distance is not sensitive to class
+	// imbalance.
+	// If Weights or ClassWeights is set, the weighted Gini gain is used
+	// instead.
+	//
+	// This option is used in Runtime.SplitMethod.
+	SplitMethodHellinger = "hellinger"
 )
 
 const (
@@ -47,6 +72,13 @@ var (
 	DEBUG = 0
 )
 
+var (
+	// ErrInvalidWeights will tell you when number of weights is not equal
+	// to number of samples.
+	ErrInvalidWeights = errors.New("cart: number of weights is not" +
+		" equal to number of samples")
+)
+
 /*
 Runtime data for building CART.
 */
@@ -57,8 +89,63 @@ type Runtime struct {
 	// otherwise select n random feature and compute gain only on selected
 	// features.
 	NRandomFeature int `json:"NRandomFeature"`
+	// FeatureWeights contain weight for each column in dataset, aligned
+	// with their column index. If its set, the random features is
+	// selected with probability proportional to their weight, so
+	// feature with zero weight will never be selected. If its nil, all
+	// features have the same probability to be selected.
+	// If fewer than NRandomFeature features have positive weight, only
+	// those features is selected; if all weights is zero, no feature is
+	// selected and the node become a leaf.
+	// It is only used when NRandomFeature is greater than zero.
+	FeatureWeights []float64 `json:"FeatureWeights"`
+	// MaxDepth define the maximum depth of tree, where the root is at
+	// depth zero. If its less or equal to zero the tree will grow until
+	// all leaf is pure or can not be splitted anymore.
+	MaxDepth int `json:"MaxDepth"`
+	// MinImpurityDecrease if its greater than zero, node will not be
+	// splitted and become a leaf with majority class if the maximum gain
+	// of all attributes is less than this value.
+	// It is only used when the gain is an impurity decrease, which is
+	// when SplitMethod is Gini (with Gini index or ImpurityFunc, e.g.
+	// entropy) or the weighted Gini gain is used. The chi-squared
+	// statistic and Hellinger distance have different scale, so it is
+	// ignored for those split methods.
+	MinImpurityDecrease float64 `json:"MinImpurityDecrease"`
 	// OOBErrVal is the last out-of-bag error value in the tree.
 	OOBErrVal float64
+	// Weights contain weight for each sample in dataset, aligned with
+	// their row index. If its empty, all samples have the same weight.
+	// If its set, the Gini index and the majority class in leaf will be
+	// computed using sum of weights instead of number of samples.
+	Weights []float64 `json:"-"`
+	// ClassWeights contain weight for each class value. If its set, the
+	// weight of each sample will be multiplied by weight of their class,
+	// so misclassification of class with higher weight cost more.
+	// Class that is not in the map have weight 1.
+	ClassWeights map[string]float64 `json:"ClassWeights"`
+	// MaxSplitCandidates if its greater than zero, only evaluate at
+	// most this number of split values, at the quantiles of samples,
+	// for each continuous attribute, instead of all midpoints.
+	// This trade a little accuracy with speed on dataset with many
+	// distinct continuous values.
+	MaxSplitCandidates int `json:"MaxSplitCandidates"`
+	// Presort if its true, each continuous attribute is sorted once
+	// before building the tree, and each child node reuse the sort order
+	// of their parent restricted to their samples, instead of sorting the
+	// attribute again in each node.
+	// It is only used when computing Gini gain without weights.
+	Presort bool `json:"Presort"`
+	// ImpurityFunc if its set, will be used to compute impurity of
+	// samples instead of Gini index when SplitMethod is Gini.
+	// It is not used if Weights or ClassWeights is set.
+	ImpurityFunc gini.ImpurityFunc `json:"-"`
+	// ExtraTrees if its true, the Gini gain of each continuous attribute
+	// is computed only on one random split value, instead of all
+	// midpoints, and the best among the random split values is used
+	// (extremely randomized trees).
+	// It is only used when computing Gini gain without weights.
+	ExtraTrees bool `json:"ExtraTrees"`
 	// Tree in classification.
 	Tree binary.Tree
 }
@@ -97,14 +184,42 @@ Build will create a tree using CART algorithm.
 func (runtime *Runtime) Build(D tabula.ClasetInterface) (e error) {
 	// Re-check input configuration.
 	switch runtime.SplitMethod {
-	case SplitMethodGini:
+	case SplitMethodGini, SplitMethodChiSquare, SplitMethodHellinger:
 		// Do nothing.
 	default:
 		// Set default split method to Gini index.
 		runtime.SplitMethod = SplitMethodGini
 	}
 
-	runtime.Tree.Root, e = runtime.splitTreeByGain(D)
+	var weights []float64
+
+	if len(runtime.Weights) > 0 {
+		if len(runtime.Weights) != D.GetNRow() {
+			return ErrInvalidWeights
+		}
+
+		// Copy the weights, because it will be sorted and splitted
+		// along with the dataset.
+		weights = make([]float64, len(runtime.Weights))
+		copy(weights, runtime.Weights)
+	}
+
+	if len(runtime.ClassWeights) > 0 {
+		weights = runtime.applyClassWeights(D, weights)
+	}
+
+	var presort [][]int
+	if runtime.Presort && len(weights) <= 0 {
+		presort = createPresort(D)
+	}
+
+	indices := make([]int, D.GetNRow())
+	for x := range indices {
+		indices[x] = x
+	}
+
+	runtime.Tree.Root, e = runtime.splitTreeByGain(D, weights, presort,
+		indices, 0)
 
 	return
 }
@@ -112,10 +227,16 @@ func (runtime *Runtime) Build(D tabula.ClasetInterface) (e error) {
 /*
 splitTreeByGain calculate the gain in all dataset, and split into two node:
 left and right.
+If `weights` is not empty, it contain the weight of each row in dataset.
+The `indices` contain the index of each row in the original dataset, which
+will be saved in leaf node.
+The `depth` is the depth of node that will be created.
 
 Return node with the split information.
 */
-func (runtime *Runtime) splitTreeByGain(D tabula.ClasetInterface) (
+func (runtime *Runtime) splitTreeByGain(D tabula.ClasetInterface,
+	weights []float64, presort [][]int, indices []int, depth int,
+) (
 	node *binary.BTNode,
 	e error,
 ) {
@@ -134,9 +255,11 @@ func (runtime *Runtime) splitTreeByGain(D tabula.ClasetInterface) (
 		}
 
 		node.Value = NodeValue{
-			IsLeaf: true,
-			Class:  D.MajorityClass(),
-			Size:   0,
+			IsLeaf:     true,
+			Class:      majorityClass(D, weights),
+			Size:       0,
+			ClassCount: classCount(D),
+			Indices:    indices,
 		}
 		return node, nil
 	}
@@ -151,9 +274,24 @@ func (runtime *Runtime) splitTreeByGain(D tabula.ClasetInterface) (
 		}
 
 		node.Value = NodeValue{
-			IsLeaf: true,
-			Class:  name,
-			Size:   nrow,
+			IsLeaf:     true,
+			Class:      name,
+			Size:       nrow,
+			ClassCount: classCount(D),
+			Indices:    indices,
+		}
+		return node, nil
+	}
+
+	// if maximum depth has been reached, return node as leaf with class
+	// is set to majority class in dataset.
+	if runtime.MaxDepth > 0 && depth >= runtime.MaxDepth {
+		node.Value = NodeValue{
+			IsLeaf:     true,
+			Class:      majorityClass(D, weights),
+			Size:       nrow,
+			ClassCount: classCount(D),
+			Indices:    indices,
 		}
 		return node, nil
 	}
@@ -163,25 +301,33 @@ func (runtime *Runtime) splitTreeByGain(D tabula.ClasetInterface) (
 	}
 
 	// calculate the Gini gain for each attribute.
-	gains := runtime.computeGain(D)
+	gains := runtime.computeGain(D, weights, presort)
 
 	// get attribute with maximum Gini gain.
 	MaxGainIdx := gini.FindMaxGain(&gains)
 	MaxGain := gains[MaxGainIdx]
 
-	// if maxgain value is 0, use majority class as node and terminate
-	// the process
-	if MaxGain.GetMaxGainValue() == 0 {
+	// if maxgain value is 0 or less than minimum impurity decrease, use
+	// majority class as node and terminate the process.
+	// The leaf hold all samples in node, so its size is the number of rows
+	// and its counted in AverageLeafSize.
+	maxGainValue := MaxGain.GetMaxGainValue()
+	isLow := runtime.isImpurityDecrease(weights) &&
+		maxGainValue < runtime.MinImpurityDecrease
+
+	if maxGainValue == 0 || isLow {
 		if DEBUG >= 2 {
-			fmt.Println("[cart] max gain 0 with target",
+			fmt.Println("[cart] max gain", maxGainValue, "with target",
 				D.GetClassAsStrings(),
 				" and majority class is ", D.MajorityClass())
 		}
 
 		node.Value = NodeValue{
-			IsLeaf: true,
-			Class:  D.MajorityClass(),
-			Size:   0,
+			IsLeaf:     true,
+			Class:      majorityClass(D, weights),
+			Size:       nrow,
+			ClassCount: classCount(D),
+			Indices:    indices,
 		}
 		return node, nil
 	}
@@ -189,6 +335,12 @@ func (runtime *Runtime) splitTreeByGain(D tabula.ClasetInterface) (
 	// using the sorted index in MaxGain, sort all field in dataset
 	tabula.SortColumnsByIndex(D, MaxGain.SortedIndex)
 
+	if len(weights) > 0 && len(MaxGain.SortedIndex) > 0 {
+		numerus.Floats64SortByIndex(&weights, MaxGain.SortedIndex)
+	}
+
+	indices = sortIndicesByIndex(indices, MaxGain.SortedIndex)
+
 	if DEBUG >= 2 {
 		fmt.Println("[cart] maxgain:", MaxGain)
 	}
@@ -222,6 +374,7 @@ func (runtime *Runtime) splitTreeByGain(D tabula.ClasetInterface) (
 		Size:          nrow,
 		SplitAttrIdx:  MaxGainIdx,
 		SplitV:        splitV,
+		Gain:          maxGainValue,
 	}
 
 	dsL, dsR, e := tabula.SplitRowsByValue(D, MaxGainIdx, splitV)
@@ -233,6 +386,13 @@ func (runtime *Runtime) splitTreeByGain(D tabula.ClasetInterface) (
 	splitL := dsL.(tabula.ClasetInterface)
 	splitR := dsR.(tabula.ClasetInterface)
 
+	weightsL, weightsR := splitWeights(D, MaxGainIdx, splitV, weights)
+
+	presortL, presortR := splitPresort(D, MaxGainIdx, splitV,
+		MaxGain.SortedIndex, presort)
+
+	indicesL, indicesR := splitIndices(D, MaxGainIdx, splitV, indices)
+
 	// Set the flag to parent in attribute referenced by
 	// MaxGainIdx, so it will not computed again in the next round.
 	cols := splitL.GetColumns()
@@ -253,12 +413,14 @@ func (runtime *Runtime) splitTreeByGain(D tabula.ClasetInterface) (
 		}
 	}
 
-	nodeLeft, e := runtime.splitTreeByGain(splitL)
+	nodeLeft, e := runtime.splitTreeByGain(splitL, weightsL, presortL,
+		indicesL, depth+1)
 	if e != nil {
 		return node, e
 	}
 
-	nodeRight, e := runtime.splitTreeByGain(splitR)
+	nodeRight, e := runtime.splitTreeByGain(splitR, weightsR, presortR,
+		indicesR, depth+1)
 	if e != nil {
 		return node, e
 	}
@@ -269,6 +431,307 @@ func (runtime *Runtime) splitTreeByGain(D tabula.ClasetInterface) (
 	return node, nil
 }
 
+//
+// applyClassWeights will multiply the weight of each sample with the weight
+// of their class. If `weights` is empty, each sample start with weight 1.
+//
+func (runtime *Runtime) applyClassWeights(D tabula.ClasetInterface,
+	weights []float64,
+) []float64 {
+	classes := D.GetClassAsStrings()
+
+	if len(weights) <= 0 {
+		weights = make([]float64, len(classes))
+		for x := range weights {
+			weights[x] = 1
+		}
+	}
+
+	for x, class := range classes {
+		cw, ok := runtime.ClassWeights[class]
+		if ok {
+			weights[x] *= cw
+		}
+	}
+
+	return weights
+}
+
+//
+// BalancedClassWeights return weight of each class in dataset that is
+// inversely proportional to their frequency,
+//
+//	number-of-samples / (number-of-classes * number-of-samples-in-class)
+//
+// which can be used as ClassWeights.
+//
+func BalancedClassWeights(D tabula.ClasetInterface) (
+	classWeights map[string]float64,
+) {
+	classes := D.GetClassAsStrings()
+	counts := make(map[string]int)
+
+	for _, class := range classes {
+		counts[class]++
+	}
+
+	n := float64(len(classes))
+	k := float64(len(counts))
+
+	classWeights = make(map[string]float64, len(counts))
+	for class, count := range counts {
+		classWeights[class] = n / (k * float64(count))
+	}
+
+	return classWeights
+}
+
+//
+// majorityClass return the class with maximum sum of weights in dataset. If
+// `weights` is empty, it will return the majority class in dataset.
+//
+func majorityClass(D tabula.ClasetInterface, weights []float64) string {
+	if len(weights) <= 0 {
+		return D.MajorityClass()
+	}
+
+	vs := D.GetClassValueSpace()
+	classWeights := make([]float64, len(vs))
+	classes := D.GetClassAsStrings()
+
+	for x, class := range classes {
+		for y, v := range vs {
+			if class == v {
+				classWeights[y] += weights[x]
+				break
+			}
+		}
+	}
+
+	_, maxIdx, ok := numerus.Floats64FindMax(classWeights)
+	if !ok {
+		return D.MajorityClass()
+	}
+
+	return vs[maxIdx]
+}
+
+//
+// isImpurityDecrease return true if the gain computed using `weights` is
+// an impurity decrease, which can be compared with MinImpurityDecrease.
+//
+func (runtime *Runtime) isImpurityDecrease(weights []float64) bool {
+	return runtime.SplitMethod == SplitMethodGini || len(weights) > 0
+}
+
+//
+// classCount return the number of samples in each class in dataset `D`.
+//
+func classCount(D tabula.ClasetInterface) map[string]int {
+	counts := make(map[string]int)
+
+	for _, class := range D.GetClassAsStrings() {
+		counts[class]++
+	}
+
+	return counts
+}
+
+//
+// sortIndicesByIndex will return the `indices` reordered using `sortedIdx`,
+// the same way as rows in dataset is sorted. If length of sortedIdx is not
+// equal to length of indices, the indices is returned as is.
+//
+func sortIndicesByIndex(indices, sortedIdx []int) []int {
+	if len(sortedIdx) != len(indices) {
+		return indices
+	}
+
+	sorted := make([]int, len(indices))
+	for x, idx := range sortedIdx {
+		sorted[x] = indices[idx]
+	}
+
+	return sorted
+}
+
+//
+// splitIndices will split the `indices` of rows using the same rule as
+// splitting the rows in dataset (see splitWeights).
+//
+func splitIndices(D tabula.ClasetInterface, attrIdx int, splitV interface{},
+	indices []int,
+) (
+	left, right []int,
+) {
+	if len(indices) != D.GetNRow() {
+		return
+	}
+
+	col := D.GetColumn(attrIdx)
+
+	for x, rec := range col.Records {
+		var isLeft bool
+
+		switch v := splitV.(type) {
+		case float64:
+			isLeft = rec.Float() < v
+		case []string:
+			isLeft = tekstus.StringsIsContain(v, rec.String())
+		case tekstus.Strings:
+			isLeft = tekstus.StringsIsContain(v, rec.String())
+		}
+
+		if isLeft {
+			left = append(left, indices[x])
+		} else {
+			right = append(right, indices[x])
+		}
+	}
+
+	return
+}
+
+//
+// splitWeights will split the `weights` using the same rule as splitting the
+// rows in dataset: row where value of attribute `attrIdx` is less than
+// `splitV` (continuous) or contained in `splitV` (discrete) goes to the left,
+// and the rest goes to the right.
+//
+func splitWeights(D tabula.ClasetInterface, attrIdx int, splitV interface{},
+	weights []float64,
+) (
+	left, right []float64,
+) {
+	if len(weights) <= 0 {
+		return
+	}
+
+	col := D.GetColumn(attrIdx)
+
+	for x, rec := range col.Records {
+		var isLeft bool
+
+		switch v := splitV.(type) {
+		case float64:
+			isLeft = rec.Float() < v
+		case []string:
+			isLeft = tekstus.StringsIsContain(v, rec.String())
+		}
+
+		if isLeft {
+			left = append(left, weights[x])
+		} else {
+			right = append(right, weights[x])
+		}
+	}
+
+	return
+}
+
+//
+// createPresort return the index of rows sorted in ascending order by value
+// of each continuous attribute in dataset `D`. Non-continuous attribute and
+// class attribute have nil index.
+//
+func createPresort(D tabula.ClasetInterface) (presort [][]int) {
+	classIdx := D.GetClassIndex()
+	cols := D.GetColumns()
+
+	presort = make([][]int, len(*cols))
+
+	for x := range *cols {
+		col := &(*cols)[x]
+
+		if x == classIdx || col.GetType() != tabula.TReal {
+			continue
+		}
+
+		attr := col.ToFloatSlice()
+		presort[x] = numerus.Floats64IndirectSort(attr, true)
+	}
+
+	return presort
+}
+
+//
+// splitPresort will split the `presort` index of dataset `D` into left and
+// right child, using the same rule as SplitRowsByValue.
+// Dataset `D` must already be sorted using `sortedIdx`, so each index in
+// presort is mapped to their new position before splitting.
+//
+func splitPresort(D tabula.ClasetInterface, attrIdx int, splitV interface{},
+	sortedIdx []int, presort [][]int,
+) (
+	left, right [][]int,
+) {
+	if len(presort) <= 0 {
+		return
+	}
+
+	nrow := D.GetNRow()
+
+	// newPos contain the position of row after dataset is sorted.
+	newPos := make([]int, nrow)
+	if len(sortedIdx) == nrow {
+		for x, idx := range sortedIdx {
+			newPos[idx] = x
+		}
+	} else {
+		for x := range newPos {
+			newPos[x] = x
+		}
+	}
+
+	// childPos contain the position of row in their child dataset.
+	isLeft := make([]bool, nrow)
+	childPos := make([]int, nrow)
+	nleft, nright := 0, 0
+
+	col := D.GetColumn(attrIdx)
+	for x, rec := range col.Records {
+		switch v := splitV.(type) {
+		case float64:
+			isLeft[x] = rec.Float() < v
+		case []string:
+			isLeft[x] = tekstus.StringsIsContain(v, rec.String())
+		case tekstus.Strings:
+			isLeft[x] = tekstus.StringsIsContain(v, rec.String())
+		}
+
+		if isLeft[x] {
+			childPos[x] = nleft
+			nleft++
+		} else {
+			childPos[x] = nright
+			nright++
+		}
+	}
+
+	left = make([][]int, len(presort))
+	right = make([][]int, len(presort))
+
+	for x, sorted := range presort {
+		if len(sorted) != nrow {
+			continue
+		}
+
+		left[x] = make([]int, 0, nleft)
+		right[x] = make([]int, 0, nright)
+
+		for _, idx := range sorted {
+			pos := newPos[idx]
+			if isLeft[pos] {
+				left[x] = append(left[x], childPos[pos])
+			} else {
+				right[x] = append(right[x], childPos[pos])
+			}
+		}
+	}
+
+	return left, right
+}
+
 // SelectRandomFeature if NRandomFeature is greater than zero, select and
 // compute gain in n random features instead of in all features
 func (runtime *Runtime) SelectRandomFeature(D tabula.ClasetInterface) {
@@ -301,8 +764,18 @@ func (runtime *Runtime) SelectRandomFeature(D tabula.ClasetInterface) {
 	// Select random features excluding feature in `excludeIdx`.
 	var pickedIdx []int
 	for x := 0; x < runtime.NRandomFeature; x++ {
-		idx := numerus.IntPickRandPositive(ncols, false, pickedIdx,
-			excludeIdx)
+		var idx int
+
+		if len(runtime.FeatureWeights) == ncols {
+			idx = runtime.pickWeightedFeature(pickedIdx, excludeIdx)
+			if idx < 0 {
+				break
+			}
+		} else {
+			idx = numerus.IntPickRandPositive(ncols, false,
+				pickedIdx, excludeIdx)
+		}
+
 		pickedIdx = append(pickedIdx, idx)
 
 		// Remove skip flag on selected column
@@ -316,18 +789,70 @@ func (runtime *Runtime) SelectRandomFeature(D tabula.ClasetInterface) {
 	}
 }
 
+//
+// pickWeightedFeature select one column index randomly with probability
+// proportional to their weight in FeatureWeights, excluding the index in
+// `pickedIdx` and `excludeIdx`. It will return -1 if no column with
+// positive weight is left, so the caller fall back to fewer candidates than
+// NRandomFeature. If all weights is zero, there is no candidate at all and
+// no feature will be selected.
+//
+func (runtime *Runtime) pickWeightedFeature(pickedIdx, excludeIdx []int) (
+	idx int,
+) {
+	candidates := make([]int, 0, len(runtime.FeatureWeights))
+	total := 0.0
+
+	for x, w := range runtime.FeatureWeights {
+		if w <= 0 || numerus.IntsIsExist(pickedIdx, x) ||
+			numerus.IntsIsExist(excludeIdx, x) {
+			continue
+		}
+
+		candidates = append(candidates, x)
+		total += w
+	}
+
+	if len(candidates) <= 0 {
+		return -1
+	}
+
+	r := rand.Float64() * total
+
+	for _, x := range candidates {
+		r -= runtime.FeatureWeights[x]
+		if r < 0 {
+			return x
+		}
+	}
+
+	return candidates[len(candidates)-1]
+}
+
 /*
 computeGain calculate the gini index for each value in each attribute.
+If `weights` is not empty, the gini index is computed using sum of weights.
 */
-func (runtime *Runtime) computeGain(D tabula.ClasetInterface) (
+func (runtime *Runtime) computeGain(D tabula.ClasetInterface,
+	weights []float64, presort [][]int,
+) (
 	gains []gini.Gini,
 ) {
 	switch runtime.SplitMethod {
-	case SplitMethodGini:
+	case SplitMethodGini, SplitMethodChiSquare, SplitMethodHellinger:
 		// create gains value for all attribute minus target class.
 		gains = make([]gini.Gini, D.GetNColumn())
 	}
 
+	isChiSquare := runtime.SplitMethod == SplitMethodChiSquare &&
+		len(weights) <= 0
+	isHellinger := runtime.SplitMethod == SplitMethodHellinger &&
+		len(weights) <= 0
+	isImpurity := runtime.SplitMethod == SplitMethodGini &&
+		len(weights) <= 0 && runtime.ImpurityFunc != nil
+	isExtraTrees := runtime.SplitMethod == SplitMethodGini &&
+		len(weights) <= 0 && runtime.ExtraTrees
+
 	runtime.SelectRandomFeature(D)
 
 	classVS := D.GetClassValueSpace()
@@ -356,7 +881,34 @@ func (runtime *Runtime) computeGain(D tabula.ClasetInterface) (
 		if col.GetType() == tabula.TReal {
 			attr := col.ToFloatSlice()
 
-			if classType == tabula.TString {
+			gains[x].MaxContinuPart = runtime.MaxSplitCandidates
+
+			if len(weights) > 0 {
+				target := D.GetClassAsStrings()
+				gains[x].ComputeContinuWeighted(&attr,
+					&target, &classVS, &weights)
+			} else if isChiSquare {
+				target := D.GetClassAsStrings()
+				gains[x].ComputeContinuChiSquare(&attr, &target,
+					&classVS)
+			} else if isHellinger {
+				target := D.GetClassAsStrings()
+				gains[x].ComputeContinuHellinger(&attr, &target,
+					&classVS)
+			} else if isImpurity {
+				target := D.GetClassAsStrings()
+				gains[x].ComputeContinuImpurity(&attr, &target,
+					&classVS, runtime.ImpurityFunc)
+			} else if isExtraTrees && classType == tabula.TString {
+				target := D.GetClassAsStrings()
+				gains[x].ComputeContinuRandom(&attr, &target,
+					&classVS)
+			} else if classType == tabula.TString &&
+				len(presort) > x && len(presort[x]) == len(attr) {
+				target := D.GetClassAsStrings()
+				gains[x].ComputeContinuSorted(&attr, &target,
+					&classVS, presort[x])
+			} else if classType == tabula.TString {
 				target := D.GetClassAsStrings()
 				gains[x].ComputeContinu(&attr, &target,
 					&classVS)
@@ -378,8 +930,22 @@ func (runtime *Runtime) computeGain(D tabula.ClasetInterface) (
 			}
 
 			target := D.GetClassAsStrings()
-			gains[x].ComputeDiscrete(&attr, &attrV, &target,
-				&classVS)
+			if len(weights) > 0 {
+				gains[x].ComputeDiscreteWeighted(&attr, &attrV,
+					&target, &classVS, &weights)
+			} else if isChiSquare {
+				gains[x].ComputeDiscreteChiSquare(&attr, &attrV,
+					&target, &classVS)
+			} else if isHellinger {
+				gains[x].ComputeDiscreteHellinger(&attr, &attrV,
+					&target, &classVS)
+			} else if isImpurity {
+				gains[x].ComputeDiscreteImpurity(&attr, &attrV,
+					&target, &classVS, runtime.ImpurityFunc)
+			} else {
+				gains[x].ComputeDiscrete(&attr, &attrV,
+					&target, &classVS)
+			}
 		}
 
 		if DEBUG >= 2 {
@@ -393,10 +959,75 @@ func (runtime *Runtime) computeGain(D tabula.ClasetInterface) (
 Classify return the prediction of one sample.
 */
 func (runtime *Runtime) Classify(data *tabula.Row) (class string) {
-	node := runtime.Tree.Root
+	leaf, _ := runtime.classify(data, false)
+	return leaf.Class
+}
+
+//
+// ClassifyDistribution return the number of training samples in each class
+// on the leaf where sample `data` is classified. The class of sample is the
+// majority class in distribution, unless the tree is build with weights.
+//
+// The returned map is shared with the leaf node and must not be modified.
+//
+func (runtime *Runtime) ClassifyDistribution(data *tabula.Row) (
+	classCount map[string]int,
+) {
+	leaf, _ := runtime.classify(data, false)
+	return leaf.ClassCount
+}
+
+//
+// ClassifyWithPath return the prediction of one sample and list of node,
+// from root to leaf, that has been traversed by the sample.
+//
+// Each non-leaf node in path contain the split attribute name, index, and
+// split value that is compared with sample's attribute value.
+//
+func (runtime *Runtime) ClassifyWithPath(data *tabula.Row) (
+	class string, path []NodeValue,
+) {
+	leaf, path := runtime.classify(data, true)
+	return leaf.Class, path
+}
+
+//
+// LeafNode return the leaf node where sample `data` is classified. Two
+// samples that is classified into the same leaf will have the same node.
+//
+func (runtime *Runtime) LeafNode(data *tabula.Row) *binary.BTNode {
+	node, _ := runtime.findLeaf(data, false)
+	return node
+}
+
+//
+// classify will traverse the tree using attribute values in `data` and return
+// the leaf node. If `withPath` is true, each node that has been visited
+// will be returned in `path`.
+//
+func (runtime *Runtime) classify(data *tabula.Row, withPath bool) (
+	leaf NodeValue, path []NodeValue,
+) {
+	node, path := runtime.findLeaf(data, withPath)
+	return node.Value.(NodeValue), path
+}
+
+//
+// findLeaf will traverse the tree using attribute values in `data` and return
+// the leaf node. If `withPath` is true, value of each node that has been
+// visited will be returned in `path`.
+//
+func (runtime *Runtime) findLeaf(data *tabula.Row, withPath bool) (
+	node *binary.BTNode, path []NodeValue,
+) {
+	node = runtime.Tree.Root
 	nodev := node.Value.(NodeValue)
 
 	for !nodev.IsLeaf {
+		if withPath {
+			path = append(path, nodev)
+		}
+
 		if nodev.IsContinu {
 			splitV := nodev.SplitV.(float64)
 			attrV := (*data)[nodev.SplitAttrIdx].Float()
@@ -419,7 +1050,11 @@ func (runtime *Runtime) Classify(data *tabula.Row) (class string) {
 		nodev = node.Value.(NodeValue)
 	}
 
-	return nodev.Class
+	if withPath {
+		path = append(path, nodev)
+	}
+
+	return node, path
 }
 
 /*
@@ -438,6 +1073,82 @@ func (runtime *Runtime) ClassifySet(data tabula.ClasetInterface) (e error) {
 	return
 }
 
+//
+// LeafCount return the number of leaf nodes in tree.
+//
+func (runtime *Runtime) LeafCount() int {
+	nleaf, _ := countLeaves(runtime.Tree.Root)
+	return nleaf
+}
+
+//
+// AverageLeafSize return the average number of training samples in each
+// leaf node. Many leaves with small size may indicate that the tree is
+// overfitting the training samples.
+//
+func (runtime *Runtime) AverageLeafSize() float64 {
+	nleaf, size := countLeaves(runtime.Tree.Root)
+	if nleaf <= 0 {
+		return 0
+	}
+	return float64(size) / float64(nleaf)
+}
+
+//
+// FeatureImportance return the importance of each column in tree, where
+// `ncol` is the number of columns in dataset used to build the tree.
+// The importance of column is the sum of impurity decrease (gain) on all
+// split node that use the column, weighted by number of samples in node.
+//
+func (runtime *Runtime) FeatureImportance(ncol int) (importances []float64) {
+	importances = make([]float64, ncol)
+	sumImportance(runtime.Tree.Root, importances)
+	return importances
+}
+
+//
+// sumImportance will add the weighted gain of each split node under `node`
+// to their split attribute in `importances`.
+//
+func sumImportance(node *binary.BTNode, importances []float64) {
+	if node == nil {
+		return
+	}
+
+	nodev, ok := node.Value.(NodeValue)
+	if !ok || nodev.IsLeaf {
+		return
+	}
+
+	if nodev.SplitAttrIdx >= 0 && nodev.SplitAttrIdx < len(importances) {
+		importances[nodev.SplitAttrIdx] += float64(nodev.Size) *
+			nodev.Gain
+	}
+
+	sumImportance(node.Left, importances)
+	sumImportance(node.Right, importances)
+}
+
+//
+// countLeaves return the number of leaf and total size of leaf nodes under
+// `node`.
+//
+func countLeaves(node *binary.BTNode) (nleaf, size int) {
+	if node == nil {
+		return 0, 0
+	}
+
+	nodev, ok := node.Value.(NodeValue)
+	if ok && nodev.IsLeaf {
+		return 1, nodev.Size
+	}
+
+	nleafL, sizeL := countLeaves(node.Left)
+	nleafR, sizeR := countLeaves(node.Right)
+
+	return nleafL + nleafR, sizeL + sizeR
+}
+
 /*
 CountOOBError process out-of-bag data on tree and return error value.
 */
@@ -492,3 +1203,73 @@ func (runtime *Runtime) String() (s string) {
 		runtime.Tree.String())
 	return s
 }
+
+//
+// ToDOT will return the tree in Graphviz DOT format.
+//
+// Each node is identified by their order in pre-order traversal, prefixed
+// with "n" (e.g. "n0" for root). Split node is labeled with split attribute
+// name, split value, and their size; leaf node is labeled with their class
+// and size. Edge to the left child is labeled with the comparison that send
+// the sample to the left, and edge to the right child with its negation.
+//
+func (runtime *Runtime) ToDOT() string {
+	var buf bytes.Buffer
+	id := 0
+
+	buf.WriteString("digraph cart {\n")
+	buf.WriteString("\tnode [shape=box];\n")
+
+	if runtime.Tree.Root != nil {
+		writeDOTNode(&buf, runtime.Tree.Root, &id)
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.String()
+}
+
+//
+// writeDOTNode will write node and their children, recursively, into `buf`
+// and return the identifier of node.
+//
+func writeDOTNode(buf *bytes.Buffer, node *binary.BTNode, id *int) (
+	nodeID string,
+) {
+	nodeID = fmt.Sprintf("n%d", *id)
+	*id++
+
+	nodev := node.Value.(NodeValue)
+
+	if nodev.IsLeaf {
+		fmt.Fprintf(buf, "\t%s [label=%q, shape=ellipse];\n", nodeID,
+			fmt.Sprintf("%s\nsize=%d", nodev.Class, nodev.Size))
+		return
+	}
+
+	var left, right string
+	if nodev.IsContinu {
+		left = fmt.Sprintf("< %v", nodev.SplitV)
+		right = fmt.Sprintf(">= %v", nodev.SplitV)
+	} else {
+		left = fmt.Sprintf("in %v", nodev.SplitV)
+		right = fmt.Sprintf("not in %v", nodev.SplitV)
+	}
+
+	fmt.Fprintf(buf, "\t%s [label=%q];\n", nodeID,
+		fmt.Sprintf("%s %s\nsize=%d", nodev.SplitAttrName, left,
+			nodev.Size))
+
+	if node.Left != nil {
+		leftID := writeDOTNode(buf, node.Left, id)
+		fmt.Fprintf(buf, "\t%s -> %s [label=%q];\n", nodeID, leftID,
+			"left: "+left)
+	}
+	if node.Right != nil {
+		rightID := writeDOTNode(buf, node.Right, id)
+		fmt.Fprintf(buf, "\t%s -> %s [label=%q];\n", nodeID, rightID,
+			"right: "+right)
+	}
+
+	return nodeID
+}
@@ -0,0 +1,88 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cart_test
+
+import (
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/classifier/cart"
+	"github.com/shuLhan/tabula"
+	"testing"
+)
+
+//
+// benchmarkBuild build CART on phoneme training set using `maxSplit` split
+// candidates, with or without presort and extra-trees, and report the
+// accuracy on test set.
+//
+func benchmarkBuild(b *testing.B, maxSplit int, presort, extraTrees bool) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/phoneme/phoneme.dsv", &samples)
+	if e != nil {
+		b.Fatal(e)
+	}
+
+	// Use the same split for all benchmark.
+	rows := samples.GetRows()
+	classIdx := samples.GetClassIndex()
+
+	var accuracy float64
+
+	b.ResetTimer()
+
+	for x := 0; x < b.N; x++ {
+		b.StopTimer()
+		trainset := samples.Clone().(*tabula.Claset)
+		testset := samples.Clone().(*tabula.Claset)
+		for y, row := range *rows {
+			if y%3 == 0 {
+				testset.PushRow(row.Clone())
+			} else {
+				trainset.PushRow(row.Clone())
+			}
+		}
+		b.StartTimer()
+
+		CART := &cart.Runtime{
+			SplitMethod:        cart.SplitMethodGini,
+			MaxSplitCandidates: maxSplit,
+			Presort:            presort,
+			ExtraTrees:         extraTrees,
+		}
+
+		e = CART.Build(trainset)
+		if e != nil {
+			b.Fatal(e)
+		}
+
+		b.StopTimer()
+		ntrue := 0
+		for _, row := range *testset.GetRows() {
+			if CART.Classify(row) == (*row)[classIdx].String() {
+				ntrue++
+			}
+		}
+		accuracy = float64(ntrue) / float64(testset.GetNRow())
+		b.StartTimer()
+	}
+
+	b.Logf("max split candidates: %d, presort: %v, extra-trees: %v,"+
+		" accuracy: %f", maxSplit, presort, extraTrees, accuracy)
+}
+
+func BenchmarkPhonemeAllSplits(b *testing.B) {
+	benchmarkBuild(b, 0, false, false)
+}
+
+func BenchmarkPhonemeSplitCandidates10(b *testing.B) {
+	benchmarkBuild(b, 10, false, false)
+}
+
+func BenchmarkPhonemePresort(b *testing.B) {
+	benchmarkBuild(b, 0, true, false)
+}
+
+func BenchmarkPhonemeExtraTrees(b *testing.B) {
+	benchmarkBuild(b, 0, false, true)
+}
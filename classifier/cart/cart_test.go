@@ -8,9 +8,14 @@ import (
 	"fmt"
 	"github.com/shuLhan/dsv"
 	"github.com/shuLhan/go-mining/classifier/cart"
+	"github.com/shuLhan/go-mining/gain/gini"
+	"github.com/shuLhan/go-mining/tree/binary"
 	"github.com/shuLhan/tabula"
+	"math"
 	"reflect"
 	"runtime/debug"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -70,3 +75,783 @@ func TestCART(t *testing.T) {
 
 	assert(t, targetv, testset.GetClassAsStrings(), true)
 }
+
+func TestClassifyWithPath(t *testing.T) {
+	fds := "../../testdata/iris/iris.dsv"
+
+	ds := tabula.Claset{}
+	_, e := dsv.SimpleRead(fds, &ds)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	CART, e := cart.New(&ds, cart.SplitMethodGini, 0)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	testset := tabula.Claset{}
+	_, e = dsv.SimpleRead(fds, &testset)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	row := testset.GetRow(NRows - 1)
+
+	class, path := CART.ClassifyWithPath(row)
+
+	fmt.Println("[cart_test] path:", path)
+
+	assert(t, CART.Classify(row), class, true)
+
+	if len(path) <= 1 {
+		t.Fatal("Expecting path with split node, got", path)
+	}
+
+	// Path must start from root.
+	root := CART.Tree.Root.Value.(cart.NodeValue)
+	assert(t, root.SplitAttrName, path[0].SplitAttrName, true)
+
+	// Only the last node is a leaf and its class is the prediction.
+	for _, nodev := range path[:len(path)-1] {
+		assert(t, false, nodev.IsLeaf, true)
+		assert(t, true, nodev.SplitAttrName != "", true)
+		assert(t, true, nodev.SplitV != nil, true)
+	}
+
+	leaf := path[len(path)-1]
+	assert(t, true, leaf.IsLeaf, true)
+	assert(t, class, leaf.Class, true)
+}
+
+func countNodes(node *binary.BTNode) int {
+	if node == nil {
+		return 0
+	}
+	return 1 + countNodes(node.Left) + countNodes(node.Right)
+}
+
+func TestToDOT(t *testing.T) {
+	ds := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/iris/iris.dsv", &ds)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	CART, e := cart.New(&ds, cart.SplitMethodGini, 0)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	dot := CART.ToDOT()
+
+	fmt.Println("[cart_test] DOT:\n", dot)
+
+	assert(t, true, strings.HasPrefix(dot, "digraph "), true)
+
+	// Count node declaration, which is line with label but without edge.
+	nnode := 0
+	for _, line := range strings.Split(dot, "\n") {
+		if strings.Contains(line, "[label=") &&
+			!strings.Contains(line, "->") {
+			nnode++
+		}
+	}
+
+	assert(t, countNodes(CART.Tree.Root), nnode, true)
+}
+
+func TestCARTWeights(t *testing.T) {
+	fds := "../../testdata/iris/iris.dsv"
+
+	ds := tabula.Claset{}
+	_, e := dsv.SimpleRead(fds, &ds)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	targetv := ds.GetClassAsStrings()
+
+	// Invalid number of weights.
+	CART := &cart.Runtime{
+		SplitMethod: cart.SplitMethodGini,
+		Weights:     []float64{1, 1},
+	}
+
+	e = CART.Build(&ds)
+	assert(t, cart.ErrInvalidWeights, e, true)
+
+	// Uniform weights must produce the same prediction as unweighted
+	// tree.
+	weights := make([]float64, ds.GetNRow())
+	for x := range weights {
+		weights[x] = 1
+	}
+
+	CART = &cart.Runtime{
+		SplitMethod: cart.SplitMethodGini,
+		Weights:     weights,
+	}
+
+	e = CART.Build(&ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	testset := tabula.Claset{}
+	_, e = dsv.SimpleRead(fds, &testset)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	testset.GetClassColumn().ClearValues()
+
+	e = CART.ClassifySet(&testset)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	assert(t, targetv, testset.GetClassAsStrings(), true)
+}
+
+func recall(actuals, predicts []string, class string) float64 {
+	var tp, n int
+	for x, act := range actuals {
+		if act != class {
+			continue
+		}
+		n++
+		if predicts[x] == class {
+			tp++
+		}
+	}
+	return float64(tp) / float64(n)
+}
+
+func TestCARTClassWeights(t *testing.T) {
+	fds := "../../testdata/phoneme/phoneme.dsv"
+
+	read := func() *tabula.Claset {
+		ds := tabula.Claset{}
+		_, e := dsv.SimpleRead(fds, &ds)
+		if nil != e {
+			t.Fatal(e)
+		}
+		return &ds
+	}
+
+	// CART sort the training samples in place, so we use different
+	// dataset for training and testing.
+	testset := read()
+
+	actuals := testset.GetClassAsStrings()
+	minority := "1"
+	maxDepth := 3
+
+	classify := func(CART *cart.Runtime) (predicts []string) {
+		for _, row := range *testset.GetRows() {
+			predicts = append(predicts, CART.Classify(row))
+		}
+		return
+	}
+
+	CART := &cart.Runtime{
+		SplitMethod: cart.SplitMethodGini,
+		MaxDepth:    maxDepth,
+	}
+
+	e := CART.Build(read())
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	unweighted := recall(actuals, classify(CART), minority)
+
+	CART = &cart.Runtime{
+		SplitMethod:  cart.SplitMethodGini,
+		MaxDepth:     maxDepth,
+		ClassWeights: cart.BalancedClassWeights(testset),
+	}
+
+	e = CART.Build(read())
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	weighted := recall(actuals, classify(CART), minority)
+
+	fmt.Printf("[cart_test] minority recall, unweighted: %f,"+
+		" balanced: %f\n", unweighted, weighted)
+
+	if weighted <= unweighted {
+		t.Fatalf("Expecting balanced minority recall %f > %f",
+			weighted, unweighted)
+	}
+}
+
+func TestSplitMethodChiSquare(t *testing.T) {
+	fds := "../../testdata/chisquare/chisquare.dsv"
+
+	// Attribute "A" separate a pure subset of majority class, which have
+	// larger Gini gain, while attribute "B" separate the only sample in
+	// class "c", which have larger chi-squared statistic.
+	exps := map[string]string{
+		cart.SplitMethodGini:      "A",
+		cart.SplitMethodChiSquare: "B",
+	}
+
+	for method, exp := range exps {
+		ds := tabula.Claset{}
+		_, e := dsv.SimpleRead(fds, &ds)
+		if nil != e {
+			t.Fatal(e)
+		}
+
+		CART := &cart.Runtime{
+			SplitMethod: method,
+			MaxDepth:    1,
+		}
+
+		e = CART.Build(&ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		fmt.Println("[cart_test] "+method+":", CART)
+
+		got := CART.Tree.Root.Value.(cart.NodeValue).SplitAttrName
+
+		assert(t, exp, got, true)
+	}
+}
+
+func TestSplitMethodHellinger(t *testing.T) {
+	fds := "../../testdata/phoneme/phoneme.dsv"
+
+	read := func() *tabula.Claset {
+		ds := tabula.Claset{}
+		_, e := dsv.SimpleRead(fds, &ds)
+		if nil != e {
+			t.Fatal(e)
+		}
+		return &ds
+	}
+
+	testset := read()
+
+	actuals := testset.GetClassAsStrings()
+	minority := "1"
+
+	recalls := make(map[string]float64)
+
+	for _, method := range []string{
+		cart.SplitMethodGini,
+		cart.SplitMethodHellinger,
+	} {
+		CART := &cart.Runtime{
+			SplitMethod: method,
+			MaxDepth:    3,
+		}
+
+		e := CART.Build(read())
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		var predicts []string
+		for _, row := range *testset.GetRows() {
+			predicts = append(predicts, CART.Classify(row))
+		}
+
+		recalls[method] = recall(actuals, predicts, minority)
+	}
+
+	giniRecall := recalls[cart.SplitMethodGini]
+	hellingerRecall := recalls[cart.SplitMethodHellinger]
+
+	fmt.Printf("[cart_test] minority recall, gini: %f, hellinger: %f\n",
+		giniRecall, hellingerRecall)
+
+	if hellingerRecall < 0.9*giniRecall {
+		t.Fatalf("Expecting Hellinger minority recall %f close to"+
+			" or above Gini %f", hellingerRecall, giniRecall)
+	}
+}
+
+func TestImpurityFunc(t *testing.T) {
+	fds := "../../testdata/iris/iris.dsv"
+
+	// giniImpurity compute Gini index in the same order as the class
+	// value space, which is sorted in iris.
+	giniImpurity := func(classCounts map[string]int, total int) float64 {
+		classes := make([]string, 0, len(classCounts))
+		for class := range classCounts {
+			classes = append(classes, class)
+		}
+		sort.Strings(classes)
+
+		n := float64(total)
+		var sump2 float64
+		for _, class := range classes {
+			p := float64(classCounts[class]) / n
+			sump2 += p * p
+		}
+		return 1 - sump2
+	}
+
+	var trees []string
+
+	for _, fn := range []gini.ImpurityFunc{nil, giniImpurity} {
+		ds := tabula.Claset{}
+		_, e := dsv.SimpleRead(fds, &ds)
+		if nil != e {
+			t.Fatal(e)
+		}
+
+		CART := &cart.Runtime{
+			SplitMethod:  cart.SplitMethodGini,
+			ImpurityFunc: fn,
+		}
+
+		e = CART.Build(&ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		trees = append(trees, fmt.Sprint(CART))
+	}
+
+	fmt.Println("[cart_test] ImpurityFunc:", trees[1])
+
+	assert(t, trees[0], trees[1], true)
+}
+
+func TestPresort(t *testing.T) {
+	fds := []string{
+		"../../testdata/iris/iris.dsv",
+		"../../testdata/phoneme/phoneme.dsv",
+	}
+
+	for _, fd := range fds {
+		var trees []string
+
+		for _, presort := range []bool{false, true} {
+			ds := tabula.Claset{}
+			_, e := dsv.SimpleRead(fd, &ds)
+			if nil != e {
+				t.Fatal(e)
+			}
+
+			CART := &cart.Runtime{
+				SplitMethod: cart.SplitMethodGini,
+				MaxDepth:    6,
+				Presort:     presort,
+			}
+
+			e = CART.Build(&ds)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			trees = append(trees, fmt.Sprint(CART))
+		}
+
+		assert(t, trees[0], trees[1], true)
+	}
+}
+
+func collectLeaves(node *binary.BTNode) (leaves []cart.NodeValue) {
+	if node == nil {
+		return nil
+	}
+
+	nodev := node.Value.(cart.NodeValue)
+	if nodev.IsLeaf {
+		return []cart.NodeValue{nodev}
+	}
+
+	leaves = append(leaves, collectLeaves(node.Left)...)
+	leaves = append(leaves, collectLeaves(node.Right)...)
+
+	return leaves
+}
+
+func TestLeafClassCount(t *testing.T) {
+	ds := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/iris/iris.dsv", &ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	CART := &cart.Runtime{
+		SplitMethod: cart.SplitMethodGini,
+		MaxDepth:    3,
+	}
+
+	e = CART.Build(&ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	total := 0
+	for _, leaf := range collectLeaves(CART.Tree.Root) {
+		sum := 0
+		for _, n := range leaf.ClassCount {
+			sum += n
+		}
+
+		assert(t, leaf.Size, sum, true)
+
+		total += sum
+	}
+
+	assert(t, ds.GetNRow(), total, true)
+
+	for _, row := range *ds.GetRows() {
+		class := CART.Classify(row)
+		dist := CART.ClassifyDistribution(row)
+
+		for c, n := range dist {
+			if n > dist[class] {
+				t.Fatalf("Expecting class %s is majority in %v,"+
+					" got %s", class, dist, c)
+			}
+		}
+	}
+}
+
+func TestMinImpurityDecrease(t *testing.T) {
+	var first, prev int
+
+	for x, minDecrease := range []float64{0, 0.05, 0.2} {
+		ds := tabula.Claset{}
+		_, e := dsv.SimpleRead("../../testdata/iris/iris.dsv", &ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		CART := &cart.Runtime{
+			SplitMethod:         cart.SplitMethodGini,
+			MinImpurityDecrease: minDecrease,
+		}
+
+		e = CART.Build(&ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		nleaf := len(collectLeaves(CART.Tree.Root))
+
+		fmt.Printf("[cart_test] min impurity decrease %f: %d leaves\n",
+			minDecrease, nleaf)
+
+		if x == 0 {
+			first = nleaf
+		} else if nleaf > prev {
+			t.Fatalf("Expecting at most %d leaves, got %d", prev,
+				nleaf)
+		}
+
+		prev = nleaf
+	}
+
+	if prev >= first {
+		t.Fatalf("Expecting less than %d leaves, got %d", first, prev)
+	}
+}
+
+func TestMinImpurityDecreaseSplitMethod(t *testing.T) {
+	entropy := func(classCounts map[string]int, total int) float64 {
+		var h float64
+		for _, n := range classCounts {
+			if n == 0 {
+				continue
+			}
+			p := float64(n) / float64(total)
+			h -= p * math.Log2(p)
+		}
+		return h
+	}
+
+	cases := []struct {
+		desc         string
+		splitMethod  string
+		impurityFunc gini.ImpurityFunc
+		minDecrease  float64
+		expLess      bool
+	}{{
+		desc:         "entropy",
+		splitMethod:  cart.SplitMethodGini,
+		impurityFunc: entropy,
+		minDecrease:  0.3,
+		expLess:      true,
+	}, {
+		desc:        "chi-square",
+		splitMethod: cart.SplitMethodChiSquare,
+		minDecrease: 1000,
+	}, {
+		desc:        "hellinger",
+		splitMethod: cart.SplitMethodHellinger,
+		minDecrease: 1000,
+	}}
+
+	for _, c := range cases {
+		nleaves := make([]int, 2)
+
+		for x, minDecrease := range []float64{0, c.minDecrease} {
+			ds := tabula.Claset{}
+			_, e := dsv.SimpleRead("../../testdata/iris/iris.dsv",
+				&ds)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			CART := &cart.Runtime{
+				SplitMethod:         c.splitMethod,
+				ImpurityFunc:        c.impurityFunc,
+				MinImpurityDecrease: minDecrease,
+			}
+
+			e = CART.Build(&ds)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			nleaves[x] = CART.LeafCount()
+		}
+
+		fmt.Printf("[cart_test] %s leaves by min impurity decrease:"+
+			" %v\n", c.desc, nleaves)
+
+		if c.expLess {
+			if nleaves[1] >= nleaves[0] {
+				t.Fatalf("%s: expecting less leaves, got %v",
+					c.desc, nleaves)
+			}
+		} else {
+			assert(t, nleaves[0], nleaves[1], true)
+		}
+	}
+}
+
+func TestZeroGainLeafSize(t *testing.T) {
+	ds := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/iris/iris.dsv", &ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// Gini gain is never greater than one, so the root become a leaf.
+	CART := &cart.Runtime{
+		SplitMethod:         cart.SplitMethodGini,
+		MinImpurityDecrease: 1,
+	}
+
+	e = CART.Build(&ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	leaves := collectLeaves(CART.Tree.Root)
+
+	assert(t, 1, len(leaves), true)
+	assert(t, ds.GetNRow(), leaves[0].Size, true)
+	assert(t, float64(ds.GetNRow()), CART.AverageLeafSize(), true)
+}
+
+func TestLeafIndices(t *testing.T) {
+	ds := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/iris/iris.dsv", &ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// CART sort the dataset in place, so keep the original class.
+	classes := ds.GetClassAsStrings()
+
+	CART := &cart.Runtime{
+		SplitMethod: cart.SplitMethodGini,
+		MaxDepth:    3,
+	}
+
+	e = CART.Build(&ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	var union []int
+
+	for _, leaf := range collectLeaves(CART.Tree.Root) {
+		counts := make(map[string]int)
+		for _, idx := range leaf.Indices {
+			counts[classes[idx]]++
+		}
+
+		assert(t, leaf.ClassCount, counts, true)
+
+		union = append(union, leaf.Indices...)
+	}
+
+	sort.Ints(union)
+
+	exp := make([]int, len(classes))
+	for x := range exp {
+		exp[x] = x
+	}
+
+	assert(t, exp, union, true)
+}
+
+func TestLeafCount(t *testing.T) {
+	nleafs := make([]int, 2)
+
+	for x, maxDepth := range []int{0, 2} {
+		ds := tabula.Claset{}
+		_, e := dsv.SimpleRead("../../testdata/iris/iris.dsv", &ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		CART := &cart.Runtime{
+			SplitMethod: cart.SplitMethodGini,
+			MaxDepth:    maxDepth,
+		}
+
+		e = CART.Build(&ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		nleafs[x] = CART.LeafCount()
+
+		fmt.Printf("[cart_test] max depth %d: %d leaves, average"+
+			" size %f\n", maxDepth, nleafs[x],
+			CART.AverageLeafSize())
+
+		assert(t, len(collectLeaves(CART.Tree.Root)), nleafs[x], true)
+		assert(t, float64(ds.GetNRow())/float64(nleafs[x]),
+			CART.AverageLeafSize(), true)
+	}
+
+	if nleafs[1] >= nleafs[0] {
+		t.Fatalf("Expecting depth limited tree have less than %d"+
+			" leaves, got %d", nleafs[0], nleafs[1])
+	}
+}
+
+func TestFeatureWeights(t *testing.T) {
+	var splitAttrs func(node *binary.BTNode, attrs map[int]int)
+
+	splitAttrs = func(node *binary.BTNode, attrs map[int]int) {
+		if node == nil {
+			return
+		}
+
+		nodev := node.Value.(cart.NodeValue)
+		if nodev.IsLeaf {
+			return
+		}
+
+		attrs[nodev.SplitAttrIdx]++
+
+		splitAttrs(node.Left, attrs)
+		splitAttrs(node.Right, attrs)
+	}
+
+	attrs := make(map[int]int)
+
+	for x := 0; x < 50; x++ {
+		ds := tabula.Claset{}
+		_, e := dsv.SimpleRead("../../testdata/iris/iris.dsv", &ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		// Petal width, at index 3, have zero weight.
+		CART := &cart.Runtime{
+			SplitMethod:    cart.SplitMethodGini,
+			NRandomFeature: 2,
+			FeatureWeights: []float64{1, 1, 1, 0, 0},
+		}
+
+		e = CART.Build(&ds)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		splitAttrs(CART.Tree.Root, attrs)
+	}
+
+	fmt.Println("[cart_test] split attributes:", attrs)
+
+	assert(t, 0, attrs[3], true)
+
+	if len(attrs) <= 1 {
+		t.Fatalf("Expecting other features selected, got %v", attrs)
+	}
+}
+
+func TestFeatureWeightsAllZero(t *testing.T) {
+	ds := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/iris/iris.dsv", &ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	CART := &cart.Runtime{
+		SplitMethod:    cart.SplitMethodGini,
+		NRandomFeature: 2,
+		FeatureWeights: []float64{0, 0, 0, 0, 0},
+	}
+
+	e = CART.Build(&ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// No feature is selected, so the root become a leaf.
+	assert(t, 1, CART.LeafCount(), true)
+}
+
+func TestExtraTrees(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/iris/iris.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	classIdx := samples.GetClassIndex()
+	trainset := samples.Clone().(*tabula.Claset)
+	testset := samples.Clone().(*tabula.Claset)
+	for x, row := range *samples.GetRows() {
+		if x%3 == 0 {
+			testset.PushRow(row.Clone())
+		} else {
+			trainset.PushRow(row.Clone())
+		}
+	}
+
+	CART := &cart.Runtime{
+		SplitMethod: cart.SplitMethodGini,
+		ExtraTrees:  true,
+	}
+
+	e = CART.Build(trainset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	ntrue := 0
+	for _, row := range *testset.GetRows() {
+		if CART.Classify(row) == (*row)[classIdx].String() {
+			ntrue++
+		}
+	}
+	accuracy := float64(ntrue) / float64(testset.GetNRow())
+
+	fmt.Println("[cart_test] extra-trees accuracy:", accuracy)
+
+	if accuracy < 0.85 {
+		t.Fatalf("Expecting accuracy at least 0.85, got %f", accuracy)
+	}
+}
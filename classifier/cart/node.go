@@ -27,6 +27,14 @@ type NodeValue struct {
 	SplitAttrIdx int
 	// SplitV define the split value.
 	SplitV interface{}
+	// Gain define the impurity decrease (gain) of split node.
+	Gain float64
+	// ClassCount contain the number of samples in each class on leaf
+	// node.
+	ClassCount map[string]int
+	// Indices contain the index of samples on leaf node, in the dataset
+	// that is used to build the tree, before its sorted by Build.
+	Indices []int
 }
 
 /*
@@ -0,0 +1,78 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package classifier
+
+//
+// ClassStat contain statistic of one class, computed by treating the class as
+// positive and all other classes as negative (one-vs-rest).
+//
+type ClassStat struct {
+	// Precision contain: tp/(tp+fp)
+	Precision float64
+	// Recall contain: tp/(tp+fn)
+	Recall float64
+	// F1 contain the harmonic mean of precision and recall.
+	F1 float64
+	// Support contain number of actual samples in class.
+	Support int64
+}
+
+//
+// PerClassReport will compute precision, recall, and F1 for each class in
+// confusion matrix `cm`.
+//
+// For each class, the true-positive is the diagonal cell, the number of
+// predicted samples is the sum of row, and the number of actual samples is
+// the sum of column.
+//
+func PerClassReport(cm *CM) (report map[string]ClassStat) {
+	nclass := len(cm.rowNames)
+	report = make(map[string]ClassStat, nclass)
+
+	tps := make([]int64, nclass)
+	predicted := make([]int64, nclass)
+	actuals := make([]int64, nclass)
+
+	rows := cm.GetDataAsRows()
+	for x, row := range *rows {
+		if x >= nclass {
+			break
+		}
+		for y, cell := range *row {
+			if y >= nclass {
+				break
+			}
+
+			v := cell.Integer()
+
+			predicted[x] += v
+			actuals[y] += v
+			if x == y {
+				tps[x] = v
+			}
+		}
+	}
+
+	for x, class := range cm.rowNames {
+		stat := ClassStat{
+			Support: actuals[x],
+		}
+
+		if predicted[x] > 0 {
+			stat.Precision = float64(tps[x]) / float64(predicted[x])
+		}
+		if actuals[x] > 0 {
+			stat.Recall = float64(tps[x]) / float64(actuals[x])
+		}
+		if stat.Precision+stat.Recall > 0 {
+			stat.F1 = 2 * stat.Precision * stat.Recall /
+				(stat.Precision + stat.Recall)
+		}
+
+		report[class] = stat
+	}
+
+	return report
+}
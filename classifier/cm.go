@@ -398,6 +398,46 @@ func (cm *CM) TN() int {
 	return int(v)
 }
 
+//
+// ClassCounts return number of actual samples for each class in confusion
+// matrix, which is the sum of each column.
+//
+func (cm *CM) ClassCounts() (counts map[string]int64) {
+	counts = make(map[string]int64, len(cm.rowNames))
+
+	classcol := cm.GetNColumn() - 1
+	rows := cm.GetDataAsRows()
+	for _, row := range *rows {
+		for y, cell := range *row {
+			if y >= classcol {
+				break
+			}
+			counts[cm.rowNames[y]] += cell.Integer()
+		}
+	}
+	return
+}
+
+//
+// PredictedCounts return number of predicted samples for each class in
+// confusion matrix, which is the sum of each row.
+//
+func (cm *CM) PredictedCounts() (counts map[string]int64) {
+	counts = make(map[string]int64, len(cm.rowNames))
+
+	classcol := cm.GetNColumn() - 1
+	rows := cm.GetDataAsRows()
+	for x, row := range *rows {
+		for y, cell := range *row {
+			if y >= classcol {
+				break
+			}
+			counts[cm.rowNames[x]] += cell.Integer()
+		}
+	}
+	return
+}
+
 //
 // TPIndices return indices of all true-positive samples.
 //
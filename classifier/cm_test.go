@@ -7,6 +7,7 @@ package classifier_test
 import (
 	"fmt"
 	"github.com/shuLhan/go-mining/classifier"
+	"math"
 	"reflect"
 	"runtime/debug"
 	"testing"
@@ -77,3 +78,95 @@ func TestGroupIndexPredictions(t *testing.T) {
 	assert(t, exp[2], cm.FPIndices(), true)
 	assert(t, exp[3], cm.TNIndices(), true)
 }
+
+func TestClassCounts(t *testing.T) {
+	vs := []string{"Iris-setosa", "Iris-versicolor", "Iris-virginica"}
+	actuals := []string{
+		vs[0], vs[0], vs[0], vs[0],
+		vs[1], vs[1], vs[1],
+		vs[2], vs[2], vs[2], vs[2], vs[2],
+	}
+	predics := []string{
+		vs[0], vs[0], vs[0], vs[1],
+		vs[1], vs[2], vs[1],
+		vs[2], vs[2], vs[1], vs[2], vs[2],
+	}
+	expClass := map[string]int64{
+		vs[0]: 4,
+		vs[1]: 3,
+		vs[2]: 5,
+	}
+	expPredicted := map[string]int64{
+		vs[0]: 3,
+		vs[1]: 4,
+		vs[2]: 5,
+	}
+
+	cm := &classifier.CM{}
+
+	cm.ComputeStrings(vs, actuals, predics)
+
+	fmt.Println(cm)
+
+	assert(t, expClass, cm.ClassCounts(), true)
+	assert(t, expPredicted, cm.PredictedCounts(), true)
+}
+
+func TestPerClassReport(t *testing.T) {
+	vs := []string{"WinF", "WinNF", "Veh", "Con", "Tabl", "Head"}
+
+	// Each class have four samples, where the first two is classified
+	// correctly and the rest is classified as "WinF", except "WinF" which
+	// is classified as "WinNF".
+	var actuals, predics []string
+	for _, class := range vs {
+		wrong := vs[0]
+		if class == vs[0] {
+			wrong = vs[1]
+		}
+
+		actuals = append(actuals, class, class, class, class)
+		predics = append(predics, class, class, wrong, wrong)
+	}
+
+	cm := &classifier.CM{}
+
+	cm.ComputeStrings(vs, actuals, predics)
+
+	fmt.Println(cm)
+
+	report := classifier.PerClassReport(cm)
+
+	// WinF: tp = 2, predicted = 2 + 2*5 = 12, actual = 4.
+	// WinNF: tp = 2, predicted = 2 + 2 = 4, actual = 4.
+	// Others: tp = 2, predicted = 2, actual = 4.
+	exp := map[string]classifier.ClassStat{
+		"WinF": {
+			Precision: 2.0 / 12.0,
+			Recall:    0.5,
+			F1:        2 * (2.0 / 12.0) * 0.5 / ((2.0 / 12.0) + 0.5),
+			Support:   4,
+		},
+		"WinNF": {Precision: 0.5, Recall: 0.5, F1: 0.5, Support: 4},
+	}
+	for _, class := range vs[2:] {
+		exp[class] = classifier.ClassStat{
+			Precision: 1,
+			Recall:    0.5,
+			F1:        2 * 0.5 / 1.5,
+			Support:   4,
+		}
+	}
+
+	assert(t, len(vs), len(report), true)
+	for class, expStat := range exp {
+		got := report[class]
+		if math.Abs(expStat.Precision-got.Precision) > 1e-9 ||
+			math.Abs(expStat.Recall-got.Recall) > 1e-9 ||
+			math.Abs(expStat.F1-got.F1) > 1e-9 ||
+			expStat.Support != got.Support {
+			t.Fatalf("%s: expecting %+v, got %+v", class, expStat,
+				got)
+		}
+	}
+}
@@ -13,6 +13,7 @@ Baumann et.al in their paper:
 package crf
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/shuLhan/go-mining/classifier"
@@ -45,6 +46,11 @@ const (
 	DefPerfFile = "crf.perf"
 	// DefStatFile default statistic file output.
 	DefStatFile = "crf.stat"
+
+	// maxRetryFactor is the maximum number of retry, relative to number
+	// of tree in stage, when the bootstrap samples contain only one
+	// class.
+	maxRetryFactor = 10
 )
 
 var (
@@ -70,6 +76,17 @@ type Runtime struct {
 	TPRate float64 `json:"TPRate"`
 	// TNRate threshold for true negative rate per stage.
 	TNRate float64 `json:"TNRate"`
+	// CostMatrix contain cost of classifying actual class (row) as
+	// predicted class (column), where index 0 is positive class and
+	// index 1 is negative class.
+	// If its set, the stage is accepted when the expected cost per
+	// sample is less or equal to the expected cost, under the same cost
+	// matrix, of stage that reach TPRate and TNRate, instead of
+	// checking TPRate and TNRate directly, and the cascade stop adding
+	// new stage after the first accepted stage.
+	// Higher cost of misclassification is harder to accept, so it will
+	// keep more stages.
+	CostMatrix [2][2]float64 `json:"CostMatrix"`
 
 	// NTree number of tree in each stage.
 	NTree int `json:"NTree"`
@@ -78,6 +95,8 @@ type Runtime struct {
 	// PercentBoot percentage of bootstrap.
 	PercentBoot int `json:"PercentBoot"`
 
+	// vs contain the class value space of training samples.
+	vs []string
 	// forests contain forest for each stage.
 	forests []*rf.Runtime
 	// weights contain weight for each stage.
@@ -122,6 +141,13 @@ func (crf *Runtime) AddForest(forest *rf.Runtime) {
 	crf.forests = append(crf.forests, forest)
 }
 
+//
+// Forests return the forest of each stage.
+//
+func (crf *Runtime) Forests() []*rf.Runtime {
+	return crf.forests
+}
+
 //
 // Initialize will check crf inputs and set it to default values if its
 // invalid.
@@ -162,6 +188,20 @@ func (crf *Runtime) Initialize(samples tabula.ClasetInterface) error {
 // Build given a sample dataset, build the stage with randomforest.
 //
 func (crf *Runtime) Build(samples tabula.ClasetInterface) (e error) {
+	return crf.BuildCtx(context.Background(), samples)
+}
+
+//
+// BuildCtx given a sample dataset, build the stage with randomforest, and
+// stop building new stage when context `ctx` is done.
+// If its stopped, the model contain all stages that has been build and it
+// will return the context error.
+// If CostMatrix is set, it will stop building new stage after one stage is
+// accepted.
+//
+func (crf *Runtime) BuildCtx(ctx context.Context,
+	samples tabula.ClasetInterface,
+) (e error) {
 	if samples == nil {
 		return ErrNoInput
 	}
@@ -171,16 +211,31 @@ func (crf *Runtime) Build(samples tabula.ClasetInterface) (e error) {
 		return
 	}
 
+	crf.vs = samples.GetClassValueSpace()
+
+	// Make sure the statistic file is closed on error.
+	defer crf.CloseOOBStatsFile()
+
 	fmt.Println(tag, "Training samples:", samples)
 	fmt.Println(tag, "Sample (one row):", samples.GetRow(0))
 	fmt.Println(tag, "Config:", crf)
 
 	for x := 0; x < crf.NStage; x++ {
+		select {
+		case <-ctx.Done():
+			e = crf.Finalize()
+			if e != nil {
+				return e
+			}
+			return ctx.Err()
+		default:
+		}
+
 		if DEBUG >= 1 {
 			fmt.Println(tag, "Stage #", x)
 		}
 
-		forest, e := crf.createForest(samples)
+		forest, isAccepted, e := crf.createForest(samples)
 		if e != nil {
 			return e
 		}
@@ -189,6 +244,10 @@ func (crf *Runtime) Build(samples tabula.ClasetInterface) (e error) {
 		if e != nil {
 			return e
 		}
+
+		if isAccepted && crf.isCostSensitive() {
+			break
+		}
 	}
 
 	return crf.Finalize()
@@ -196,13 +255,14 @@ func (crf *Runtime) Build(samples tabula.ClasetInterface) (e error) {
 
 //
 // createForest will create and return a forest and run the training `samples`
-// on it.
+// on it. It also return true if the stage is accepted.
 //
 // Algorithm,
 // (1) Initialize forest.
 // (2) For 0 to maximum number of tree in forest,
-// (2.1) grow one tree until success.
-// (2.2) If tree tp-rate and tn-rate greater than threshold, stop growing.
+// (2.1) grow one tree. If the bootstrap samples contain only one class,
+// retry up to ten times number of tree in stage, otherwise return the error.
+// (2.2) If stage is accepted, stop growing.
 // (3) Calculate weight.
 // (4) TODO: Move true-negative from samples. The collection of true-negative
 // will be used again to test the model and after test and the sample with FP
@@ -210,7 +270,7 @@ func (crf *Runtime) Build(samples tabula.ClasetInterface) (e error) {
 // (5) Refill samples with false-positive.
 //
 func (crf *Runtime) createForest(samples tabula.ClasetInterface) (
-	forest *rf.Runtime, e error,
+	forest *rf.Runtime, isAccepted bool, e error,
 ) {
 	var cm *classifier.CM
 	var stat *classifier.Stat
@@ -224,14 +284,21 @@ func (crf *Runtime) createForest(samples tabula.ClasetInterface) (
 		},
 		NTree:          crf.NTree,
 		NRandomFeature: crf.NRandomFeature,
+		PercentBoot:    crf.PercentBoot,
 	}
 
 	e = forest.Initialize(samples)
 	if e != nil {
-		return nil, e
+		return nil, false, e
 	}
 
+	// Make sure the forest statistic file is closed on error.
+	defer forest.CloseOOBStatsFile()
+
 	// (2)
+	nretry := 0
+	maxRetry := maxRetryFactor * crf.NTree
+
 	for t := 0; t < crf.NTree; t++ {
 		if DEBUG >= 2 {
 			fmt.Println(tag, "Tree #", t)
@@ -243,18 +310,22 @@ func (crf *Runtime) createForest(samples tabula.ClasetInterface) (
 			if e == nil {
 				break
 			}
+			if e != rf.ErrSingleClassBag || nretry >= maxRetry {
+				return nil, false, e
+			}
+			nretry++
 		}
 
 		// (2.2)
-		if stat.TPRate > crf.TPRate &&
-			stat.TNRate > crf.TNRate {
+		if crf.isStageAccepted(stat) {
+			isAccepted = true
 			break
 		}
 	}
 
 	e = forest.Finalize()
 	if e != nil {
-		return nil, e
+		return nil, false, e
 	}
 
 	// (3)
@@ -272,7 +343,58 @@ func (crf *Runtime) createForest(samples tabula.ClasetInterface) (
 
 	samples.RecountMajorMinor()
 
-	return forest, nil
+	return forest, isAccepted, nil
+}
+
+//
+// isStageAccepted return true if the statistic of stage reach the threshold.
+// If CostMatrix is not set, the TP rate and TN rate must be greater than
+// TPRate and TNRate. Otherwise, the expected cost must be less or equal to
+// the expected cost of stage that reach TPRate and TNRate, so scaling the
+// CostMatrix does not change the result.
+//
+func (crf *Runtime) isStageAccepted(stat *classifier.Stat) bool {
+	if !crf.isCostSensitive() {
+		return stat.TPRate > crf.TPRate && stat.TNRate > crf.TNRate
+	}
+
+	n := float64(stat.TP + stat.FN + stat.FP + stat.TN)
+	if n == 0 {
+		return false
+	}
+
+	cost := (float64(stat.TP)*crf.CostMatrix[0][0] +
+		float64(stat.FN)*crf.CostMatrix[0][1] +
+		float64(stat.FP)*crf.CostMatrix[1][0] +
+		float64(stat.TN)*crf.CostMatrix[1][1]) / n
+
+	// Expected cost of stage that reach TPRate and TNRate, using the
+	// same cost matrix.
+	c := crf.CostMatrix
+	ppos := float64(stat.TP+stat.FN) / n
+	pneg := float64(stat.FP+stat.TN) / n
+	threshold := ppos*((1-crf.TPRate)*c[0][1]+crf.TPRate*c[0][0]) +
+		pneg*((1-crf.TNRate)*c[1][0]+crf.TNRate*c[1][1])
+
+	if DEBUG >= 2 {
+		fmt.Println(tag, "Expected cost:", cost, "threshold:", threshold)
+	}
+
+	return cost <= threshold
+}
+
+//
+// isCostSensitive return true if one of the value in CostMatrix is not zero.
+//
+func (crf *Runtime) isCostSensitive() bool {
+	for _, row := range crf.CostMatrix {
+		for _, v := range row {
+			if v != 0 {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 //
@@ -481,3 +603,51 @@ func (crf *Runtime) ClassifySetByWeight(samples tabula.ClasetInterface,
 
 	return predicts, cm, probs
 }
+
+//
+// Predict return the class of `row` and the probability of each class in
+// value space of training samples, with the same order, where the
+// probability is the average of class probability in each stage weighted by
+// the stage weight.
+// It will return empty class and nil probabilities if cascade has not been
+// build.
+//
+func (crf *Runtime) Predict(row *tabula.Row) (class string, probs []float64) {
+	if len(crf.forests) <= 0 || len(crf.vs) <= 0 {
+		return "", nil
+	}
+
+	probs = make([]float64, len(crf.vs))
+	sumWeights := numerus.Floats64Sum(crf.weights)
+
+	for x, forest := range crf.forests {
+		votes := forest.Votes(row, -1)
+		stageProbs := tekstus.WordsProbabilitiesOf(votes, crf.vs, false)
+
+		for y := range stageProbs {
+			probs[y] += stageProbs[y] * crf.weights[x]
+		}
+	}
+
+	maxIdx := 0
+	for y := range probs {
+		if sumWeights > 0 {
+			probs[y] /= sumWeights
+		}
+		if probs[y] > probs[maxIdx] {
+			maxIdx = y
+		}
+	}
+
+	return crf.vs[maxIdx], probs
+}
+
+//
+// Classify return the class of `row` with maximum weighted probability, so
+// cascade can be used as classifier.Classifier, e.g. in OneVsRest or
+// cross-validation.
+//
+func (crf *Runtime) Classify(row *tabula.Row) (class string) {
+	class, _ = crf.Predict(row)
+	return class
+}
@@ -5,11 +5,18 @@
 package crf_test
 
 import (
+	"context"
 	"fmt"
 	"github.com/shuLhan/dsv"
 	"github.com/shuLhan/go-mining/classifier"
 	"github.com/shuLhan/go-mining/classifier/crf"
+	"github.com/shuLhan/go-mining/classifier/rf"
+	"github.com/shuLhan/go-mining/dataset"
 	"github.com/shuLhan/tabula"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"reflect"
 	"testing"
 )
 
@@ -29,11 +36,7 @@ func runCRF(t *testing.T) {
 		t.Fatal(e)
 	}
 
-	nbag := (samples.Len() * 63) / 100
-	train, test, _, testIds := tabula.RandomPickRows(&samples, nbag, false)
-
-	trainset := train.(tabula.ClasetInterface)
-	testset := test.(tabula.ClasetInterface)
+	trainset, testset := dataset.TrainTestSplit(&samples, 0.63, 1, true)
 
 	crf := crf.Runtime{
 		Runtime: classifier.Runtime{
@@ -52,7 +55,7 @@ func runCRF(t *testing.T) {
 	testset.RecountMajorMinor()
 	fmt.Println("Testset:", testset)
 
-	predicts, cm, probs := crf.ClassifySetByWeight(testset, testIds)
+	predicts, cm, probs := crf.ClassifySetByWeight(testset, nil)
 
 	fmt.Println("Confusion matrix:", cm)
 
@@ -79,3 +82,228 @@ func TestPhoneme200_10(t *testing.T) {
 
 	runCRF(t)
 }
+
+func TestCostMatrix(t *testing.T) {
+	costs := [][2][2]float64{
+		{{0, 1}, {1, 0}},
+		// High cost of false-negative.
+		{{0, 20}, {1, 0}},
+	}
+	nstages := make([]int, len(costs))
+
+	for x, cost := range costs {
+		samples := tabula.Claset{}
+		_, e := dsv.SimpleRead("../../testdata/phoneme/phoneme.dsv",
+			&samples)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		crf := crf.Runtime{
+			Runtime: classifier.Runtime{
+				StatFile: "phoneme_cost.stat",
+				PerfFile: "phoneme_cost.perf",
+			},
+			NStage:     10,
+			NTree:      10,
+			CostMatrix: cost,
+		}
+
+		// Use the same seed, so both cascade grow the same trees.
+		rand.Seed(1)
+
+		e = crf.Build(&samples)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		nstages[x] = len(crf.Forests())
+	}
+
+	fmt.Println("[crf_test] number of stages by cost:", nstages)
+
+	if nstages[1] <= nstages[0] {
+		t.Fatalf("Expecting high false-negative cost keep more stages,"+
+			" got %v", nstages)
+	}
+}
+
+func TestCostMatrixScale(t *testing.T) {
+	costs := [][2][2]float64{
+		{{0, 20}, {1, 0}},
+		{{0, 200}, {10, 0}},
+	}
+	ntrees := make([][]int, len(costs))
+
+	for x, cost := range costs {
+		samples := tabula.Claset{}
+		_, e := dsv.SimpleRead("../../testdata/phoneme/phoneme.dsv",
+			&samples)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		crf := crf.Runtime{
+			Runtime: classifier.Runtime{
+				StatFile: "phoneme_cost.stat",
+				PerfFile: "phoneme_cost.perf",
+			},
+			NStage:     2,
+			NTree:      30,
+			CostMatrix: cost,
+		}
+
+		rand.Seed(1)
+
+		e = crf.Build(&samples)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		for _, forest := range crf.Forests() {
+			ntrees[x] = append(ntrees[x], len(forest.Trees()))
+		}
+	}
+
+	fmt.Println("[crf_test] number of trees by scaled cost:", ntrees)
+
+	if !reflect.DeepEqual(ntrees[0], ntrees[1]) {
+		t.Fatalf("Expecting scaling cost matrix grow the same trees,"+
+			" got %v", ntrees)
+	}
+}
+
+func TestOneVsRest(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/iris/iris.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	ovr := classifier.OneVsRest{
+		NewBinary: func() classifier.Classifier {
+			return &crf.Runtime{
+				Runtime: classifier.Runtime{
+					OOBStatsFile: "iris.crf.oob",
+					StatFile:     "iris.crf.stat",
+					PerfFile:     "iris.crf.perf",
+				},
+				NStage: 5,
+				NTree:  10,
+			}
+		},
+	}
+
+	e = ovr.Build(&samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// Each cascade is scored by probability of its positive class.
+	row := samples.GetRow(0)
+
+	for x, c := range ovr.Classifiers() {
+		p, ok := c.(classifier.Predictor)
+		if !ok {
+			t.Fatalf("Expecting cascade %d is a predictor", x)
+		}
+
+		_, probs := p.Predict(row)
+		if len(probs) != 2 {
+			t.Fatalf("Expecting two probabilities of cascade %d,"+
+				" got %v", x, probs)
+		}
+	}
+}
+
+//
+// readPhoneme return the phoneme samples for testing.
+//
+func readPhoneme(t *testing.T) *tabula.Claset {
+	samples := &tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/phoneme/phoneme.dsv", samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+	return samples
+}
+
+func TestBuildCtxCancel(t *testing.T) {
+	samples := readPhoneme(t)
+
+	crf := crf.Runtime{
+		Runtime: classifier.Runtime{
+			OOBStatsFile: "phoneme_cancel.oob",
+			StatFile:     "phoneme_cancel.stat",
+			PerfFile:     "phoneme_cancel.perf",
+		},
+		NStage: NStage,
+		NTree:  NTree,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	e := crf.BuildCtx(ctx, samples)
+	if e != context.Canceled {
+		t.Fatalf("Expecting error %v, got %v", context.Canceled, e)
+	}
+
+	nstage := len(crf.Forests())
+	if nstage != 0 {
+		t.Fatalf("Expecting no stage after cancel, got %d", nstage)
+	}
+}
+
+func TestBuildErrorCloseStatsFile(t *testing.T) {
+	const fdDir = "/proc/self/fd"
+
+	if _, e := os.Stat(fdDir); e != nil {
+		t.Skip("can not count open files:", e)
+	}
+
+	// Use only 100 samples, so one percent bootstrap contain one
+	// sample.
+	phoneme := readPhoneme(t)
+	bag, _, _, _ := tabula.RandomPickRows(phoneme, 100, false)
+
+	samples := bag.(tabula.ClasetInterface)
+	samples.SetClassIndex(phoneme.GetClassIndex())
+
+	countFD := func() int {
+		fds, e := ioutil.ReadDir(fdDir)
+		if e != nil {
+			t.Fatal(e)
+		}
+		return len(fds)
+	}
+
+	before := countFD()
+
+	for x := 0; x < 10; x++ {
+		// Bootstrap with only one sample always contain one class,
+		// so the first stage fail after the statistic file is
+		// opened.
+		crf := crf.Runtime{
+			Runtime: classifier.Runtime{
+				OOBStatsFile: "phoneme_error.oob",
+				StatFile:     "phoneme_error.stat",
+				PerfFile:     "phoneme_error.perf",
+			},
+			NStage:      1,
+			NTree:       1,
+			PercentBoot: 1,
+		}
+
+		e := crf.Build(samples)
+		if e != rf.ErrSingleClassBag {
+			t.Fatalf("Expecting error %v, got %v",
+				rf.ErrSingleClassBag, e)
+		}
+	}
+
+	after := countFD()
+	if after != before {
+		t.Fatalf("Expecting %d open files, got %d", before, after)
+	}
+}
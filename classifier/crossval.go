@@ -0,0 +1,207 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package classifier
+
+import (
+	"errors"
+	"github.com/shuLhan/tabula"
+	"math/rand"
+)
+
+var (
+	// ErrInvalidTestFraction will tell you when fraction of test samples
+	// is not between zero and one, or when it leave no samples for
+	// training.
+	ErrInvalidTestFraction = errors.New("classifier: invalid fraction of" +
+		" test samples")
+	// ErrInvalidNSplit will tell you when number of splits is less or
+	// equal to zero.
+	ErrInvalidNSplit = errors.New("classifier: number of splits must be" +
+		" greater than zero")
+	// ErrTooFewSamples will tell you when there is not enough samples for
+	// validation.
+	ErrTooFewSamples = errors.New("classifier: not enough samples for" +
+		" validation")
+)
+
+//
+// Classifier is the interface for model that can be build from samples and
+// classify a new row (e.g. cart.Runtime and rf.Runtime).
+//
+type Classifier interface {
+	Build(samples tabula.ClasetInterface) error
+	Classify(row *tabula.Row) string
+}
+
+//
+// NewClassifierFunc is a function that return new, un-trained, classifier
+// for each evaluation in cross-validation.
+//
+type NewClassifierFunc func() Classifier
+
+//
+// LeaveOneOut will evaluate classifier from `newc` on each sample, where
+// the classifier is build using all other samples.
+// It return the mean of statistic, and statistic of each evaluation.
+// On multi-class samples, only the accuracy and OOB error is computed from
+// all classes, other statistic only use the first two classes in value
+// space.
+//
+func LeaveOneOut(samples tabula.ClasetInterface, newc NewClassifierFunc) (
+	mean *Stat, stats Stats, e error,
+) {
+	rows := samples.GetDataAsRows()
+	nrow := rows.Len()
+	if nrow < 2 {
+		return nil, nil, ErrTooFewSamples
+	}
+
+	for x := 0; x < nrow; x++ {
+		trainIds := make([]int, 0, nrow-1)
+		for y := 0; y < nrow; y++ {
+			if y != x {
+				trainIds = append(trainIds, y)
+			}
+		}
+
+		stat, e := evaluate(samples, trainIds, []int{x}, newc)
+		if e != nil {
+			return nil, nil, e
+		}
+
+		stat.ID = int64(x)
+		stats.Add(stat)
+	}
+
+	return meanStat(stats), stats, nil
+}
+
+//
+// MonteCarloCV will evaluate classifier from `newc` on `nSplits` random
+// split of samples, where `testFraction` of samples is used for testing and
+// the rest for training. The random split is generated using `seed`.
+// It return the mean of statistic, and statistic of each evaluation.
+// As in LeaveOneOut, only the accuracy and OOB error is computed from all
+// classes.
+//
+func MonteCarloCV(samples tabula.ClasetInterface, newc NewClassifierFunc,
+	nSplits int, testFraction float64, seed int64,
+) (
+	mean *Stat, stats Stats, e error,
+) {
+	if nSplits <= 0 {
+		return nil, nil, ErrInvalidNSplit
+	}
+	if testFraction <= 0 || testFraction >= 1 {
+		return nil, nil, ErrInvalidTestFraction
+	}
+
+	nrow := samples.GetNRow()
+
+	ntest := int(float64(nrow) * testFraction)
+	if ntest <= 0 {
+		ntest = 1
+	}
+	if ntest >= nrow {
+		return nil, nil, ErrInvalidTestFraction
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+
+	for x := 0; x < nSplits; x++ {
+		perm := rng.Perm(nrow)
+
+		stat, e := evaluate(samples, perm[ntest:], perm[:ntest], newc)
+		if e != nil {
+			return nil, nil, e
+		}
+
+		stat.ID = int64(x)
+		stats.Add(stat)
+	}
+
+	return meanStat(stats), stats, nil
+}
+
+//
+// evaluate will build new classifier using samples in `trainIds` and
+// compute the statistic of classifying samples in `testIds`.
+//
+// The accuracy and OOB error is computed from all classes. The TP, FP, TN,
+// FN, rates, precision, and F-measure is only meaningful for binary
+// classification, where the first class in value space is the positive
+// class and the second class is the negative class.
+//
+func evaluate(samples tabula.ClasetInterface, trainIds, testIds []int,
+	newc NewClassifierFunc,
+) (
+	stat *Stat, e error,
+) {
+	rows := samples.GetDataAsRows()
+
+	trainset := samples.Clone().(tabula.ClasetInterface)
+	for _, id := range trainIds {
+		trainset.PushRow((*rows)[id].Clone())
+	}
+
+	c := newc()
+
+	e = c.Build(trainset)
+	if e != nil {
+		return nil, e
+	}
+
+	classIdx := samples.GetClassIndex()
+	actuals := make([]string, 0, len(testIds))
+	predicts := make([]string, 0, len(testIds))
+
+	for _, id := range testIds {
+		row := (*rows)[id]
+
+		actuals = append(actuals, (*row)[classIdx].String())
+		predicts = append(predicts, c.Classify(row))
+	}
+
+	cm := CM{}
+	cm.ComputeStrings(samples.GetClassValueSpace(), actuals, predicts)
+
+	rt := Runtime{}
+	stat = &Stat{}
+	rt.ComputeStatFromCM(stat, &cm)
+
+	// ComputeStatFromCM compute accuracy from the first two classes
+	// only, use the whole diagonal of confusion matrix instead.
+	stat.Accuracy = cm.GetTrueRate()
+
+	return stat, nil
+}
+
+//
+// meanStat return the mean of rates in all `stats`. The TP, FP, TN, and FN
+// is the total from all stats.
+//
+func meanStat(stats Stats) (mean *Stat) {
+	mean = &Stat{}
+
+	n := float64(len(stats))
+	if n == 0 {
+		return
+	}
+
+	for _, stat := range stats {
+		mean.Sum(stat)
+	}
+
+	mean.OobError /= n
+	mean.OobErrorMean /= n
+	mean.TPRate /= n
+	mean.FPRate /= n
+	mean.TNRate /= n
+	mean.Precision /= n
+	mean.FMeasure /= n
+	mean.Accuracy /= n
+
+	return mean
+}
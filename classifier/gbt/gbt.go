@@ -0,0 +1,405 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package gbt implement gradient boosted trees for binary classification by
+Friedman, using log-loss as the loss function and regression tree as the base
+learner.
+
+	Friedman, Jerome H. "Greedy function approximation: a gradient boosting
+	machine." Annals of statistics (2001): 1189-1232.
+
+The first class in value space is used as the positive class.
+
+The base learner is the regression tree in this package instead of
+cart.Runtime, because CART only grow classification tree on class labels,
+while each round of boosting fit the real-valued residuals of log-loss.
+The regression tree split on numeric attribute only, so all attributes must
+be real or integer. Nominal attribute must be encoded into numeric value
+first (e.g. using dataset.LabelEncode and dataset.ApplyLabelEncode),
+otherwise Build will return ErrNominalAttribute.
+*/
+package gbt
+
+import (
+	"errors"
+	"fmt"
+	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/tabula"
+	"math"
+	"os"
+	"strconv"
+)
+
+const (
+	tag = "[gbt]"
+
+	// DefNumRound default number of boosting round.
+	DefNumRound = 100
+
+	// DefLearningRate default shrinkage of each tree output.
+	DefLearningRate = 0.1
+
+	// DefMaxDepth default maximum depth of each regression tree.
+	DefMaxDepth = 3
+
+	// DefOOBStatsFile default statistic file output for each round.
+	DefOOBStatsFile = "gbt.oob.stat"
+
+	// DefPerfFile default performance file output.
+	DefPerfFile = "gbt.perf"
+
+	// DefStatFile default statistic file.
+	DefStatFile = "gbt.stat"
+
+	// probEpsilon is used to clip probability away from 0 and 1.
+	probEpsilon = 1e-15
+)
+
+var (
+	// DEBUG level, can be set from environment "GBT_DEBUG".
+	DEBUG = 0
+)
+
+var (
+	// ErrNoInput will tell you when no input is given.
+	ErrNoInput = errors.New("gbt: input samples is empty")
+	// ErrNotBinary will tell you when samples does not have two classes.
+	ErrNotBinary = errors.New("gbt: samples must have two classes")
+	// ErrNominalAttribute will tell you when samples contain attribute
+	// that is not real or integer.
+	ErrNominalAttribute = errors.New("gbt: attribute must be real or" +
+		" integer")
+)
+
+/*
+Runtime contains input and output configuration when generating gradient
+boosted trees.
+*/
+type Runtime struct {
+	// Runtime embed common fields for classifier.
+	classifier.Runtime
+
+	// NRound number of boosting round or number of trees.
+	NRound int `json:"NRound"`
+	// LearningRate shrink the output of each tree.
+	LearningRate float64 `json:"LearningRate"`
+	// MaxDepth maximum depth of each tree.
+	MaxDepth int `json:"MaxDepth"`
+
+	// positive contain the class used as positive class.
+	positive string
+	// initScore contain the initial log-odds of positive class.
+	initScore float64
+	// trees contain all regression trees.
+	trees []regTree
+	// logLosses contain the log-loss on training samples after each
+	// round.
+	logLosses []float64
+}
+
+func init() {
+	var e error
+	DEBUG, e = strconv.Atoi(os.Getenv("GBT_DEBUG"))
+	if e != nil {
+		DEBUG = 0
+	}
+}
+
+//
+// LogLosses return the log-loss on training samples after each round.
+//
+func (gbt *Runtime) LogLosses() []float64 {
+	return gbt.logLosses
+}
+
+//
+// NTrees return number of trees that has been build.
+//
+func (gbt *Runtime) NTrees() int {
+	return len(gbt.trees)
+}
+
+//
+// Initialize will check inputs and set it to default values if invalid.
+// It will return ErrNotBinary if samples does not have two classes, or
+// ErrNominalAttribute if samples contain nominal attribute.
+//
+func (gbt *Runtime) Initialize(samples tabula.ClasetInterface) error {
+	if gbt.NRound <= 0 {
+		gbt.NRound = DefNumRound
+	}
+	if gbt.LearningRate <= 0 {
+		gbt.LearningRate = DefLearningRate
+	}
+	if gbt.MaxDepth <= 0 {
+		gbt.MaxDepth = DefMaxDepth
+	}
+	if gbt.OOBStatsFile == "" {
+		gbt.OOBStatsFile = DefOOBStatsFile
+	}
+	if gbt.PerfFile == "" {
+		gbt.PerfFile = DefPerfFile
+	}
+	if gbt.StatFile == "" {
+		gbt.StatFile = DefStatFile
+	}
+
+	vs := samples.GetClassValueSpace()
+	if len(vs) != 2 {
+		return ErrNotBinary
+	}
+
+	classIdx := samples.GetClassIndex()
+	for x := 0; x < samples.GetNColumn(); x++ {
+		if x == classIdx {
+			continue
+		}
+		switch samples.GetColumn(x).GetType() {
+		case tabula.TReal, tabula.TInteger:
+		default:
+			return ErrNominalAttribute
+		}
+	}
+
+	gbt.positive = vs[0]
+	gbt.trees = nil
+	gbt.logLosses = nil
+
+	return gbt.Runtime.Initialize()
+}
+
+//
+// Build will train the trees using samples.
+//
+// Algorithm,
+//
+// (0) Initialize the score of each sample with log-odds of positive class.
+// (1) For 0 to NRound,
+// (1.1) compute probability of each sample from their score,
+// (1.2) compute the negative gradient (residual) and hessian of log-loss,
+// (1.3) fit regression tree to residuals,
+// (1.4) update score of each sample with learning rate times tree output,
+// (1.5) compute log-loss and statistic on training samples.
+// (2) Compute and write total statistic.
+//
+func (gbt *Runtime) Build(samples tabula.ClasetInterface) (e error) {
+	if samples == nil || samples.GetNRow() <= 0 {
+		return ErrNoInput
+	}
+
+	e = gbt.Initialize(samples)
+	if e != nil {
+		return
+	}
+
+	// Make sure the statistic file is closed on error.
+	defer gbt.CloseOOBStatsFile()
+
+	fmt.Println(tag, "Training set    :", samples)
+	fmt.Println(tag, "Config          :", gbt)
+
+	attrs := gbt.attributes(samples)
+	actuals := samples.GetClassAsStrings()
+	nrow := len(actuals)
+
+	labels := make([]float64, nrow)
+	npos := 0.0
+	for x, class := range actuals {
+		if class == gbt.positive {
+			labels[x] = 1
+			npos++
+		}
+	}
+
+	// (0)
+	p := clipProb(npos / float64(nrow))
+	gbt.initScore = math.Log(p / (1 - p))
+
+	scores := make([]float64, nrow)
+	for x := range scores {
+		scores[x] = gbt.initScore
+	}
+
+	res := make([]float64, nrow)
+	hess := make([]float64, nrow)
+	probs := make([]float64, nrow)
+
+	// (1)
+	for t := 0; t < gbt.NRound; t++ {
+		stat := &classifier.Stat{}
+		stat.ID = int64(t)
+		stat.Start()
+
+		// (1.1) and (1.2)
+		for x := range scores {
+			p = sigmoid(scores[x])
+			res[x] = labels[x] - p
+			hess[x] = p * (1 - p)
+		}
+
+		// (1.3)
+		tree := regTree{
+			maxDepth: gbt.MaxDepth,
+		}
+		tree.build(attrs, res, hess)
+		gbt.trees = append(gbt.trees, tree)
+
+		// (1.4)
+		for x := range scores {
+			scores[x] += gbt.LearningRate * tree.predict(attrs[x])
+			probs[x] = sigmoid(scores[x])
+		}
+
+		// (1.5)
+		loss := logLoss(labels, probs)
+		gbt.logLosses = append(gbt.logLosses, loss)
+
+		if DEBUG >= 1 {
+			fmt.Println(tag, "round #", t, "log-loss:", loss)
+		}
+
+		predicts := gbt.predicts(probs, samples.GetClassValueSpace())
+		cm := gbt.ComputeCM(nil, samples.GetClassValueSpace(), actuals,
+			predicts)
+		gbt.AddOOBCM(cm)
+
+		stat.End()
+		gbt.AddStat(stat)
+		gbt.ComputeStatFromCM(stat, cm)
+		gbt.ComputeStatTotal(stat)
+
+		e = gbt.WriteOOBStat(stat)
+		if e != nil {
+			return e
+		}
+	}
+
+	// (2)
+	return gbt.Finalize()
+}
+
+//
+// Probability return the probability of `sample` being in positive class.
+//
+func (gbt *Runtime) Probability(sample *tabula.Row, classIdx int) float64 {
+	attr := sampleAttributes(sample, classIdx)
+
+	score := gbt.initScore
+	for _, tree := range gbt.trees {
+		score += gbt.LearningRate * tree.predict(attr)
+	}
+
+	return sigmoid(score)
+}
+
+//
+// ClassifySet given a samples predict their class, and return their class
+// prediction, confusion matrix, and probability of positive class for each
+// sample.
+//
+func (gbt *Runtime) ClassifySet(samples tabula.ClasetInterface,
+	sampleIds []int,
+) (
+	predicts []string, cm *classifier.CM, probs []float64,
+) {
+	stat := classifier.Stat{}
+	stat.Start()
+
+	vs := samples.GetClassValueSpace()
+	actuals := samples.GetClassAsStrings()
+	classIdx := samples.GetClassIndex()
+
+	rows := samples.GetRows()
+	for _, row := range *rows {
+		probs = append(probs, gbt.Probability(row, classIdx))
+	}
+
+	predicts = gbt.predicts(probs, vs)
+
+	cm = gbt.ComputeCM(sampleIds, vs, actuals, predicts)
+
+	gbt.ComputeStatFromCM(&stat, cm)
+	stat.End()
+
+	if len(sampleIds) <= 0 {
+		fmt.Println(tag, "CM:", cm)
+		fmt.Println(tag, "Classifying stat:", stat)
+		_ = stat.Write(gbt.StatFile)
+	}
+
+	return predicts, cm, probs
+}
+
+//
+// predicts will convert probabilities of positive class into class label,
+// using 0.5 as threshold.
+//
+func (gbt *Runtime) predicts(probs []float64, vs []string) (
+	predicts []string,
+) {
+	negative := vs[1]
+	if negative == gbt.positive {
+		negative = vs[0]
+	}
+
+	predicts = make([]string, len(probs))
+	for x, p := range probs {
+		if p >= 0.5 {
+			predicts[x] = gbt.positive
+		} else {
+			predicts[x] = negative
+		}
+	}
+	return
+}
+
+//
+// attributes return the value of all attributes, except class, in samples.
+//
+func (gbt *Runtime) attributes(samples tabula.ClasetInterface) (
+	attrs [][]float64,
+) {
+	classIdx := samples.GetClassIndex()
+	rows := samples.GetRows()
+
+	attrs = make([][]float64, len(*rows))
+	for x, row := range *rows {
+		attrs[x] = sampleAttributes(row, classIdx)
+	}
+	return
+}
+
+//
+// sampleAttributes return attribute values in `row` except the class.
+//
+func sampleAttributes(row *tabula.Row, classIdx int) (attr []float64) {
+	for x, rec := range *row {
+		if x == classIdx {
+			continue
+		}
+		attr = append(attr, rec.Float())
+	}
+	return
+}
+
+func sigmoid(v float64) float64 {
+	return 1 / (1 + math.Exp(-v))
+}
+
+func clipProb(p float64) float64 {
+	return math.Min(math.Max(p, probEpsilon), 1-probEpsilon)
+}
+
+//
+// logLoss compute the mean of negative log-likelihood of binary `labels`
+// given the probabilities of positive class.
+//
+func logLoss(labels, probs []float64) (loss float64) {
+	for x, y := range labels {
+		p := clipProb(probs[x])
+		loss -= y*math.Log(p) + (1-y)*math.Log(1-p)
+	}
+	return loss / float64(len(labels))
+}
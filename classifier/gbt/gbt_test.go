@@ -0,0 +1,91 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gbt_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/go-mining/classifier/gbt"
+	"github.com/shuLhan/tabula"
+	"testing"
+)
+
+func TestGBTPhoneme(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/phoneme/phoneme.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	gb := gbt.Runtime{
+		Runtime: classifier.Runtime{
+			OOBStatsFile: "phoneme.gbt.oob",
+			StatFile:     "phoneme.gbt.stat",
+			PerfFile:     "phoneme.gbt.perf",
+		},
+		NRound: 20,
+	}
+
+	e = gb.Build(&samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	losses := gb.LogLosses()
+
+	fmt.Println("[gbt_test] log-loss per round:", losses)
+
+	if len(losses) != gb.NRound {
+		t.Fatalf("Expecting %d log-loss, got %d", gb.NRound,
+			len(losses))
+	}
+
+	// Log-loss on training set must not increase on each round.
+	for x := 1; x < len(losses); x++ {
+		if losses[x] > losses[x-1]+1e-9 {
+			t.Fatalf("Log-loss increase at round %d: %f > %f", x,
+				losses[x], losses[x-1])
+		}
+	}
+	if losses[len(losses)-1] >= losses[0] {
+		t.Fatal("Expecting log-loss to decrease over rounds")
+	}
+
+	_, cm, probs := gb.ClassifySet(&samples, nil)
+
+	fmt.Println("[gbt_test] CM:", cm)
+
+	for _, p := range probs {
+		if p < 0 || p > 1 {
+			t.Fatal("Expecting probability in [0,1], got", p)
+		}
+	}
+}
+
+func TestNominalAttribute(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/phoneme/phoneme.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	samples.GetColumn(0).SetType(tabula.TString)
+
+	gb := gbt.Runtime{
+		Runtime: classifier.Runtime{
+			OOBStatsFile: "phoneme.gbt.oob",
+			StatFile:     "phoneme.gbt.stat",
+			PerfFile:     "phoneme.gbt.perf",
+		},
+		NRound: 1,
+	}
+
+	e = gb.Build(&samples)
+	if e != gbt.ErrNominalAttribute {
+		t.Fatalf("Expecting error %v, got %v", gbt.ErrNominalAttribute,
+			e)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gbt
+
+import (
+	"fmt"
+	"sort"
+)
+
+//
+// treeNode is a node in regression tree.
+//
+type treeNode struct {
+	// IsLeaf define whether node is a leaf or not.
+	IsLeaf bool
+	// Value is the output of leaf node.
+	Value float64
+	// SplitAttrIdx define the attribute which cause the split.
+	SplitAttrIdx int
+	// SplitV define the split value. Sample with attribute value less
+	// than SplitV goes to the left.
+	SplitV float64
+	// Left branch of node.
+	Left *treeNode
+	// Right branch of node.
+	Right *treeNode
+}
+
+//
+// regTree is a binary regression tree, where each split is selected by
+// maximum reduction of the sum of squared error of residuals.
+//
+type regTree struct {
+	// maxDepth maximum depth of tree.
+	maxDepth int
+	// root of tree.
+	root *treeNode
+}
+
+//
+// build will create regression tree to fit the residuals `res` of samples
+// `attrs`. The `hess` is the second derivative of loss for each sample,
+// which is used to compute the value of leaf.
+//
+func (tree *regTree) build(attrs [][]float64, res, hess []float64) {
+	ids := make([]int, len(res))
+	for x := range ids {
+		ids[x] = x
+	}
+
+	tree.root = tree.split(attrs, res, hess, ids, 0)
+}
+
+//
+// split will split the samples at `ids` by attribute and value that have the
+// maximum reduction of squared error.
+//
+func (tree *regTree) split(attrs [][]float64, res, hess []float64, ids []int,
+	depth int,
+) (
+	node *treeNode,
+) {
+	node = &treeNode{}
+
+	if depth >= tree.maxDepth || len(ids) <= 1 {
+		node.IsLeaf = true
+		node.Value = leafValue(res, hess, ids)
+		return node
+	}
+
+	bestGain := 0.0
+	bestAttr := -1
+	bestV := 0.0
+
+	nattr := len(attrs[ids[0]])
+	sorted := make([]int, len(ids))
+
+	var sum float64
+	for _, id := range ids {
+		sum += res[id]
+	}
+	n := float64(len(ids))
+
+	for a := 0; a < nattr; a++ {
+		copy(sorted, ids)
+		sort.Slice(sorted, func(i, j int) bool {
+			return attrs[sorted[i]][a] < attrs[sorted[j]][a]
+		})
+
+		var sumLeft float64
+		for x := 0; x < len(sorted)-1; x++ {
+			sumLeft += res[sorted[x]]
+
+			v := attrs[sorted[x]][a]
+			next := attrs[sorted[x+1]][a]
+			if v == next {
+				continue
+			}
+
+			nleft := float64(x + 1)
+			nright := n - nleft
+			sumRight := sum - sumLeft
+
+			// Reduction of squared error, without the constant part.
+			gain := (sumLeft*sumLeft)/nleft +
+				(sumRight*sumRight)/nright - (sum*sum)/n
+
+			if gain > bestGain {
+				bestGain = gain
+				bestAttr = a
+				bestV = (v + next) / 2
+			}
+		}
+	}
+
+	if bestAttr < 0 {
+		node.IsLeaf = true
+		node.Value = leafValue(res, hess, ids)
+		return node
+	}
+
+	var left, right []int
+	for _, id := range ids {
+		if attrs[id][bestAttr] < bestV {
+			left = append(left, id)
+		} else {
+			right = append(right, id)
+		}
+	}
+
+	if DEBUG >= 3 {
+		fmt.Printf("%s split attr %d at %f, gain %f\n", tag, bestAttr,
+			bestV, bestGain)
+	}
+
+	node.SplitAttrIdx = bestAttr
+	node.SplitV = bestV
+	node.Left = tree.split(attrs, res, hess, left, depth+1)
+	node.Right = tree.split(attrs, res, hess, right, depth+1)
+
+	return node
+}
+
+//
+// leafValue compute the value of leaf using one Newton-Raphson step,
+//
+//	sum(residuals) / sum(hessians)
+//
+func leafValue(res, hess []float64, ids []int) float64 {
+	var sumRes, sumHess float64
+
+	for _, id := range ids {
+		sumRes += res[id]
+		sumHess += hess[id]
+	}
+
+	if sumHess == 0 {
+		return 0
+	}
+
+	return sumRes / sumHess
+}
+
+//
+// predict return the output of leaf for sample attributes `attr`.
+//
+func (tree *regTree) predict(attr []float64) float64 {
+	node := tree.root
+
+	for !node.IsLeaf {
+		if attr[node.SplitAttrIdx] < node.SplitV {
+			node = node.Left
+		} else {
+			node = node.Right
+		}
+	}
+
+	return node.Value
+}
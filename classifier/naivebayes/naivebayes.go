@@ -0,0 +1,370 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package naivebayes implement the naive Bayes classifier, where the likelihood
+of continuous (real) attribute is modeled using Gaussian distribution, and the
+likelihood of discrete attribute is modeled using categorical distribution
+with Laplace (additive) smoothing.
+*/
+package naivebayes
+
+import (
+	"errors"
+	"fmt"
+	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/tabula"
+	"math"
+	"os"
+	"strconv"
+)
+
+const (
+	tag = "[naivebayes]"
+
+	// DefAlpha default value of additive smoothing for discrete
+	// attribute.
+	DefAlpha = 1.0
+
+	// DefStatFile default statistic file.
+	DefStatFile = "naivebayes.stat"
+
+	// varSmoothing is the fraction of the largest variance of all
+	// continuous attribute that is added to each variance, to prevent
+	// division by zero.
+	varSmoothing = 1e-9
+)
+
+var (
+	// DEBUG level, can be set from environment "NAIVEBAYES_DEBUG".
+	DEBUG = 0
+)
+
+var (
+	// ErrNoInput will tell you when no input is given.
+	ErrNoInput = errors.New("naivebayes: input samples is empty")
+)
+
+/*
+Runtime contains input and output configuration when generating naive Bayes
+classifier.
+*/
+type Runtime struct {
+	// Runtime embed common fields for classifier.
+	classifier.Runtime
+
+	// Alpha additive smoothing parameter for discrete attribute.
+	// Default to DefAlpha.
+	Alpha float64 `json:"Alpha"`
+
+	// classIndex contain index of class attribute in training samples.
+	classIndex int
+	// vs contain the class value space.
+	vs []string
+	// logPriors contain log of prior probability of each class.
+	logPriors []float64
+	// isContinu will be true if attribute at index is continuous.
+	isContinu []bool
+	// means contain mean of continuous attribute for each class.
+	means [][]float64
+	// variances contain variance of continuous attribute for each class.
+	variances [][]float64
+	// counts contain the number of each value of discrete attribute for
+	// each class.
+	counts [][]map[string]float64
+	// nvalues contain number of unique values of discrete attribute.
+	nvalues []int
+	// nclass contain number of samples in each class.
+	nclass []float64
+}
+
+func init() {
+	var e error
+	DEBUG, e = strconv.Atoi(os.Getenv("NAIVEBAYES_DEBUG"))
+	if e != nil {
+		DEBUG = 0
+	}
+}
+
+//
+// Initialize will check inputs and set it to default values if invalid.
+//
+func (nb *Runtime) Initialize() {
+	if nb.Alpha <= 0 {
+		nb.Alpha = DefAlpha
+	}
+	if nb.StatFile == "" {
+		nb.StatFile = DefStatFile
+	}
+}
+
+/*
+Build will compute the prior of each class and the likelihood parameters of
+each attribute using samples.
+
+Algorithm,
+
+(0) Check input and initialize default values.
+(1) Count the samples in each class and compute their log-prior.
+(2) For each continuous attribute, compute the mean and variance in each
+class.
+(3) For each discrete attribute, count each value in each class.
+(4) Add small fraction of the largest variance into all variances.
+*/
+func (nb *Runtime) Build(samples tabula.ClasetInterface) (e error) {
+	if samples == nil || samples.GetNRow() <= 0 {
+		return ErrNoInput
+	}
+
+	// (0)
+	nb.Initialize()
+
+	nb.classIndex = samples.GetClassIndex()
+	nb.vs = samples.GetClassValueSpace()
+
+	ncol := samples.GetNColumn()
+	nclass := len(nb.vs)
+	rows := samples.GetRows()
+	classes := samples.GetClassAsStrings()
+
+	classIds := make([]int, len(classes))
+	for x, class := range classes {
+		classIds[x] = nb.classIndexOf(class)
+	}
+
+	// (1)
+	nb.nclass = make([]float64, nclass)
+	for _, c := range classIds {
+		if c >= 0 {
+			nb.nclass[c]++
+		}
+	}
+
+	nb.logPriors = make([]float64, nclass)
+	for c, n := range nb.nclass {
+		nb.logPriors[c] = math.Log(n / float64(len(classes)))
+	}
+
+	nb.isContinu = make([]bool, ncol)
+	nb.nvalues = make([]int, ncol)
+	nb.means = make([][]float64, nclass)
+	nb.variances = make([][]float64, nclass)
+	nb.counts = make([][]map[string]float64, nclass)
+
+	for c := 0; c < nclass; c++ {
+		nb.means[c] = make([]float64, ncol)
+		nb.variances[c] = make([]float64, ncol)
+		nb.counts[c] = make([]map[string]float64, ncol)
+	}
+
+	maxVar := 0.0
+
+	for x := 0; x < ncol; x++ {
+		if x == nb.classIndex {
+			continue
+		}
+
+		col := samples.GetColumn(x)
+
+		if col.GetType() == tabula.TReal {
+			// (2)
+			nb.isContinu[x] = true
+
+			for y, row := range *rows {
+				c := classIds[y]
+				if c >= 0 {
+					nb.means[c][x] += (*row)[x].Float()
+				}
+			}
+			for c := range nb.means {
+				if nb.nclass[c] > 0 {
+					nb.means[c][x] /= nb.nclass[c]
+				}
+			}
+
+			for y, row := range *rows {
+				c := classIds[y]
+				if c >= 0 {
+					d := (*row)[x].Float() - nb.means[c][x]
+					nb.variances[c][x] += d * d
+				}
+			}
+			for c := range nb.variances {
+				if nb.nclass[c] > 0 {
+					nb.variances[c][x] /= nb.nclass[c]
+				}
+				if nb.variances[c][x] > maxVar {
+					maxVar = nb.variances[c][x]
+				}
+			}
+			continue
+		}
+
+		// (3)
+		for c := range nb.counts {
+			nb.counts[c][x] = make(map[string]float64)
+		}
+
+		values := make(map[string]bool)
+		for y, row := range *rows {
+			v := (*row)[x].String()
+			values[v] = true
+
+			c := classIds[y]
+			if c >= 0 {
+				nb.counts[c][x][v]++
+			}
+		}
+		nb.nvalues[x] = len(values)
+	}
+
+	// (4)
+	epsilon := varSmoothing * maxVar
+	if epsilon <= 0 {
+		epsilon = varSmoothing
+	}
+
+	for c := range nb.variances {
+		for x := range nb.variances[c] {
+			if nb.isContinu[x] {
+				nb.variances[c][x] += epsilon
+			}
+		}
+	}
+
+	if DEBUG >= 1 {
+		fmt.Println(tag, "class value space:", nb.vs)
+		fmt.Println(tag, "means:", nb.means)
+		fmt.Println(tag, "variances:", nb.variances)
+	}
+
+	return nil
+}
+
+//
+// classIndexOf return the index of `class` in value space, or -1 if its not
+// found.
+//
+func (nb *Runtime) classIndexOf(class string) int {
+	for x, v := range nb.vs {
+		if v == class {
+			return x
+		}
+	}
+	return -1
+}
+
+//
+// logLikelihood return the log of likelihood of `row` in class at index `c`.
+//
+func (nb *Runtime) logLikelihood(row *tabula.Row, c int) (ll float64) {
+	for x, rec := range *row {
+		if x == nb.classIndex || x >= len(nb.isContinu) {
+			continue
+		}
+
+		if nb.isContinu[x] {
+			variance := nb.variances[c][x]
+			d := rec.Float() - nb.means[c][x]
+
+			ll -= 0.5*math.Log(2*math.Pi*variance) +
+				d*d/(2*variance)
+			continue
+		}
+
+		count := nb.counts[c][x][rec.String()]
+		ll += math.Log((count + nb.Alpha) /
+			(nb.nclass[c] + nb.Alpha*float64(nb.nvalues[x])))
+	}
+	return ll
+}
+
+//
+// Predict return the class of `row` and the posterior probability of each
+// class in value space of training samples, with the same order.
+// It will return empty class and nil probabilities if classifier has not
+// been build.
+//
+func (nb *Runtime) Predict(row *tabula.Row) (class string, probs []float64) {
+	if len(nb.vs) <= 0 {
+		return "", nil
+	}
+
+	probs = make([]float64, len(nb.vs))
+
+	maxIdx := 0
+	for c := range nb.vs {
+		probs[c] = nb.logPriors[c] + nb.logLikelihood(row, c)
+		if probs[c] > probs[maxIdx] {
+			maxIdx = c
+		}
+	}
+
+	// Normalize the log posterior using log-sum-exp.
+	maxLog := probs[maxIdx]
+	sum := 0.0
+	for c := range probs {
+		probs[c] = math.Exp(probs[c] - maxLog)
+		sum += probs[c]
+	}
+	for c := range probs {
+		probs[c] /= sum
+	}
+
+	return nb.vs[maxIdx], probs
+}
+
+//
+// Classify return the class of `row` with maximum posterior probability.
+//
+func (nb *Runtime) Classify(row *tabula.Row) (class string) {
+	class, _ = nb.Predict(row)
+	return class
+}
+
+//
+// ClassifySet given a samples predict their class by maximum posterior
+// probability, and return their class prediction, confusion matrix, and
+// posterior probability of the first class in value space for each sample.
+//
+func (nb *Runtime) ClassifySet(samples tabula.ClasetInterface,
+	sampleIds []int,
+) (
+	predicts []string, cm *classifier.CM, probs []float64,
+) {
+	stat := classifier.Stat{}
+	stat.Start()
+
+	vs := samples.GetClassValueSpace()
+	actuals := samples.GetClassAsStrings()
+
+	rows := samples.GetRows()
+	for _, row := range *rows {
+		class, classProbs := nb.Predict(row)
+
+		prob := 0.0
+		if len(vs) > 0 {
+			c := nb.classIndexOf(vs[0])
+			if c >= 0 {
+				prob = classProbs[c]
+			}
+		}
+
+		predicts = append(predicts, class)
+		probs = append(probs, prob)
+	}
+
+	cm = nb.ComputeCM(sampleIds, vs, actuals, predicts)
+
+	nb.ComputeStatFromCM(&stat, cm)
+	stat.End()
+
+	if len(sampleIds) <= 0 {
+		fmt.Println(tag, "CM:", cm)
+		fmt.Println(tag, "Classifying stat:", stat)
+		_ = stat.Write(nb.StatFile)
+	}
+
+	return predicts, cm, probs
+}
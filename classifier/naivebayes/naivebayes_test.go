@@ -0,0 +1,165 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package naivebayes_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/go-mining/classifier/naivebayes"
+	"github.com/shuLhan/go-mining/dataset"
+	"github.com/shuLhan/tabula"
+	"math"
+	"testing"
+)
+
+const (
+	SampleFile = "../../testdata/iris/iris.dsv"
+)
+
+func TestNaiveBayesIris(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead(SampleFile, &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	trainset, testset := dataset.TrainTestSplit(&samples, 0.7, 1, true)
+
+	nb := naivebayes.Runtime{
+		Runtime: classifier.Runtime{
+			StatFile: "iris.naivebayes.stat",
+		},
+	}
+
+	class, probs := nb.Predict(testset.GetRow(0))
+	if class != "" || probs != nil {
+		t.Fatalf("Expecting empty prediction before build, got %q %v",
+			class, probs)
+	}
+
+	e = nb.Build(trainset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	predicts, cm, firstProbs := nb.ClassifySet(testset, nil)
+
+	if len(predicts) != testset.GetNRow() ||
+		len(firstProbs) != testset.GetNRow() {
+		t.Fatalf("Expecting %d predictions, got %d and %d"+
+			" probabilities", testset.GetNRow(), len(predicts),
+			len(firstProbs))
+	}
+
+	for _, row := range *testset.GetRows() {
+		_, probs = nb.Predict(row)
+
+		sum := 0.0
+		for _, p := range probs {
+			sum += p
+		}
+		if math.Abs(sum-1) > 1e-9 {
+			t.Fatalf("Expecting sum of probabilities is 1, got %f",
+				sum)
+		}
+	}
+
+	accuracy := cm.GetTrueRate()
+
+	fmt.Println("[naivebayes_test] accuracy:", accuracy)
+
+	if accuracy < 0.9 {
+		t.Fatalf("Expecting accuracy at least 0.9, got %f", accuracy)
+	}
+}
+
+func TestBuildEmpty(t *testing.T) {
+	nb := naivebayes.Runtime{}
+
+	e := nb.Build(nil)
+	if e != naivebayes.ErrNoInput {
+		t.Fatalf("Expecting error %v, got %v", naivebayes.ErrNoInput,
+			e)
+	}
+}
+
+var nominalConfig = []byte(`{
+	"Input"			:"unused.dat"
+,	"MaxRows"		:-1
+,	"ClassIndex"		:2
+,	"DatasetMode"		:"matrix"
+,	"InputMetadata"		:
+	[{
+		"Name"			:"outlook"
+	,	"Separator"		:","
+	,	"Type"			:"string"
+	},{
+		"Name"			:"windy"
+	,	"Separator"		:","
+	,	"Type"			:"string"
+	},{
+		"Name"			:"play"
+	,	"Type"			:"string"
+	,	"ValueSpace"		:
+		[
+			"yes"
+		,	"no"
+		]
+	}]
+}`)
+
+var nominalData = []byte(`sunny,false,no
+sunny,true,no
+overcast,false,yes
+rainy,false,yes
+rainy,true,no
+overcast,true,yes
+`)
+
+func TestNaiveBayesNominal(t *testing.T) {
+	samples, e := dataset.ReadFromBytes(nominalConfig, nominalData)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	nb := naivebayes.Runtime{
+		Runtime: classifier.Runtime{
+			StatFile: "nominal.naivebayes.stat",
+		},
+	}
+
+	e = nb.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	row := &tabula.Row{
+		tabula.NewRecordString("overcast"),
+		tabula.NewRecordString("true"),
+		tabula.NewRecordString("yes"),
+	}
+
+	class, probs := nb.Predict(row)
+
+	// With Laplace smoothing, overcast is never seen in class "no",
+	//
+	//	P(yes|row) ~ (2+1)/(3+3) * (1+1)/(3+2) = 0.2
+	//	P(no|row)  ~ (0+1)/(3+3) * (2+1)/(3+2) = 0.1
+	//
+	exp := []float64{2.0 / 3.0, 1.0 / 3.0}
+
+	fmt.Println("[naivebayes_test] nominal:", class, probs)
+
+	if class != "yes" {
+		t.Fatalf("Expecting class yes, got %s", class)
+	}
+	for x := range exp {
+		if math.Abs(exp[x]-probs[x]) > 1e-9 {
+			t.Fatalf("Expecting probabilities %v, got %v", exp,
+				probs)
+		}
+	}
+}
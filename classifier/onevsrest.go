@@ -0,0 +1,161 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package classifier
+
+import (
+	"errors"
+	"github.com/shuLhan/tabula"
+)
+
+const (
+	// restPrefix is the prefix of negative class name in binary samples
+	// created by OneVsRest, e.g. "!setosa" for class "setosa".
+	restPrefix = "!"
+)
+
+var (
+	// ErrNoNewClassifier will tell you when the function to create
+	// binary classifier in OneVsRest is not set.
+	ErrNoNewClassifier = errors.New("classifier: function to create" +
+		" binary classifier is not set")
+)
+
+//
+// Predictor is classifier that can return the probability of each class in
+// value space of its training samples, with the same order (e.g.
+// rf.Runtime).
+//
+type Predictor interface {
+	Predict(row *tabula.Row) (class string, probs []float64)
+}
+
+//
+// OneVsRest turn binary classifier into multi-class classifier, by building
+// one binary classifier for each class, where the class is the positive
+// class and all other classes is the negative class.
+//
+type OneVsRest struct {
+	// NewBinary return new, un-trained, binary classifier.
+	NewBinary NewClassifierFunc
+
+	// vs contain the class value space of training samples.
+	vs []string
+	// classifiers contain binary classifier for each class in value
+	// space.
+	classifiers []Classifier
+}
+
+//
+// Classifiers return the binary classifier of each class, with the same
+// order as class value space of training samples.
+//
+func (ovr *OneVsRest) Classifiers() []Classifier {
+	return ovr.classifiers
+}
+
+/*
+Build will create and build one binary classifier for each class in samples.
+
+Algorithm,
+
+(1) For each class in value space,
+(1.1) copy the samples, and change the class of each row into the class
+itself (positive) or the class prefixed with "!" (negative). The class is
+always stored as string, even if the class in samples is numeric,
+(1.2) set the class value space of binary samples, where the positive class
+is the first class, and
+(1.3) build new binary classifier using binary samples.
+*/
+func (ovr *OneVsRest) Build(samples tabula.ClasetInterface) (e error) {
+	if ovr.NewBinary == nil {
+		return ErrNoNewClassifier
+	}
+
+	ovr.vs = samples.GetClassValueSpace()
+	ovr.classifiers = nil
+
+	classIdx := samples.GetClassIndex()
+	rows := samples.GetRows()
+
+	// (1)
+	for _, positive := range ovr.vs {
+		negative := restPrefix + positive
+
+		// (1.1)
+		binset := samples.Clone().(tabula.ClasetInterface)
+		for _, row := range *rows {
+			class := (*row)[classIdx].String()
+			if class != positive {
+				class = negative
+			}
+
+			newRow := row.Clone()
+			(*newRow)[classIdx] = tabula.NewRecordString(class)
+			binset.PushRow(newRow)
+		}
+
+		// (1.2)
+		classCol := binset.GetClassColumn()
+		classCol.SetType(tabula.TString)
+		classCol.ValueSpace = []string{positive, negative}
+		binset.SetClassIndex(classIdx)
+		binset.RecountMajorMinor()
+
+		// (1.3)
+		c := ovr.NewBinary()
+
+		e = c.Build(binset)
+		if e != nil {
+			return e
+		}
+
+		ovr.classifiers = append(ovr.classifiers, c)
+	}
+
+	return nil
+}
+
+//
+// Scores return the score of each class in value space of training samples
+// on `row`. If binary classifier implement Predictor, the score is the
+// probability of positive class, otherwise the score is one if its classify
+// the row as positive class or zero if not.
+//
+func (ovr *OneVsRest) Scores(row *tabula.Row) (scores []float64) {
+	scores = make([]float64, len(ovr.classifiers))
+
+	for x, c := range ovr.classifiers {
+		if p, ok := c.(Predictor); ok {
+			_, probs := p.Predict(row)
+			if len(probs) > 0 {
+				scores[x] = probs[0]
+			}
+			continue
+		}
+
+		if c.Classify(row) == ovr.vs[x] {
+			scores[x] = 1
+		}
+	}
+
+	return scores
+}
+
+//
+// Classify return the class with maximum score on `row`. If more than one
+// class have the maximum score, the first one in value space is returned.
+//
+func (ovr *OneVsRest) Classify(row *tabula.Row) (class string) {
+	max := 0.0
+
+	for x, score := range ovr.Scores(row) {
+		if x == 0 || score > max {
+			class = ovr.vs[x]
+			max = score
+		}
+	}
+
+	return class
+}
@@ -0,0 +1,127 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package classifier_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/go-mining/classifier/cart"
+	"github.com/shuLhan/go-mining/classifier/rf"
+	"github.com/shuLhan/go-mining/dataset"
+	"github.com/shuLhan/tabula"
+	"testing"
+)
+
+func TestOneVsRest(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../testdata/iris/iris.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	trainset, testset := dataset.TrainTestSplit(&samples, 0.7, 1, true)
+
+	ovr := classifier.OneVsRest{}
+
+	e = ovr.Build(trainset)
+	assert(t, classifier.ErrNoNewClassifier, e, true)
+
+	ovr.NewBinary = func() classifier.Classifier {
+		return &rf.Runtime{
+			Runtime: classifier.Runtime{
+				OOBStatsFile: "iris.ovr.oob",
+			},
+			NTree: 20,
+		}
+	}
+
+	e = ovr.Build(trainset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	vs := trainset.GetClassValueSpace()
+
+	assert(t, len(vs), len(ovr.Classifiers()), true)
+
+	actuals := testset.GetClassAsStrings()
+	predicts := make([]string, len(actuals))
+	for x, row := range *testset.GetRows() {
+		predicts[x] = ovr.Classify(row)
+	}
+
+	accuracy := classifier.AccuracyOf(actuals, predicts)
+
+	fmt.Println("[classifier_test] one-vs-rest accuracy:", accuracy)
+
+	if accuracy < 0.9 {
+		t.Fatalf("Expecting accuracy at least 0.9, got %f", accuracy)
+	}
+}
+
+var numericClassConfig = []byte(`{
+	"Input"			:"unused.dat"
+,	"MaxRows"		:-1
+,	"ClassIndex"		:1
+,	"DatasetMode"		:"matrix"
+,	"InputMetadata"		:
+	[{
+		"Name"			:"x"
+	,	"Separator"		:","
+	,	"Type"			:"real"
+	},{
+		"Name"			:"class"
+	,	"Type"			:"integer"
+	,	"ValueSpace"		:
+		[
+			"1"
+		,	"2"
+		,	"3"
+		]
+	}]
+}`)
+
+var numericClassData = []byte(`1.0,1
+1.2,1
+0.9,1
+5.0,2
+5.1,2
+4.8,2
+9.0,3
+9.2,3
+8.9,3
+`)
+
+func TestOneVsRestNumericClass(t *testing.T) {
+	samples, e := dataset.ReadFromBytes(numericClassConfig,
+		numericClassData)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	ovr := classifier.OneVsRest{
+		NewBinary: func() classifier.Classifier {
+			return &cart.Runtime{
+				SplitMethod: cart.SplitMethodGini,
+			}
+		},
+	}
+
+	e = ovr.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, 3, len(ovr.Classifiers()), true)
+
+	// The class in training samples is not changed.
+	assert(t, tabula.TInteger, samples.GetClassType(), true)
+
+	for _, row := range *samples.GetRows() {
+		exp := (*row)[1].String()
+		assert(t, exp, ovr.Classify(row), true)
+	}
+}
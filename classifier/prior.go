@@ -0,0 +1,34 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package classifier
+
+import (
+	"github.com/shuLhan/tabula"
+)
+
+//
+// ClassPriors return the prior probability of each class in `samples`, which
+// is the frequency of class divided by number of samples.
+// It will return an empty map if samples is empty.
+//
+func ClassPriors(samples tabula.ClasetInterface) (priors map[string]float64) {
+	priors = make(map[string]float64)
+
+	classes := samples.GetClassAsStrings()
+	if len(classes) <= 0 {
+		return
+	}
+
+	for _, class := range classes {
+		priors[class]++
+	}
+
+	n := float64(len(classes))
+	for class := range priors {
+		priors[class] /= n
+	}
+
+	return
+}
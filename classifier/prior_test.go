@@ -0,0 +1,47 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package classifier_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/tabula"
+	"math"
+	"testing"
+)
+
+func TestClassPriors(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../testdata/phoneme/phoneme.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	priors := classifier.ClassPriors(&samples)
+
+	fmt.Println("[classifier_test] class priors:", priors)
+
+	assert(t, 2, len(priors), true)
+
+	sum := 0.0
+	for _, p := range priors {
+		sum += p
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Fatalf("Expecting sum of priors is 1, got %f", sum)
+	}
+
+	// Class "0" is the majority class in phoneme.
+	if priors["0"] <= priors["1"] {
+		t.Fatalf("Expecting prior of majority class %f greater than"+
+			" minority %f", priors["0"], priors["1"])
+	}
+
+	// Empty samples.
+	empty := samples.Clone().(*tabula.Claset)
+
+	assert(t, map[string]float64{}, classifier.ClassPriors(empty), true)
+}
@@ -0,0 +1,248 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rf
+
+import (
+	"github.com/shuLhan/tabula"
+	"math"
+)
+
+const (
+	// CalibrationPlatt calibrate the probabilities using Platt scaling,
+	// by fitting a sigmoid function on probability of positive class.
+	CalibrationPlatt = "platt"
+)
+
+const (
+	// plattMaxIter maximum number of Newton iterations in fitPlatt.
+	plattMaxIter = 100
+	// plattMinStep minimum step size of line search in fitPlatt.
+	plattMinStep = 1e-10
+	// plattSigma small value added to Hessian diagonal in fitPlatt.
+	plattSigma = 1e-12
+	// plattEps stopping criteria of gradient in fitPlatt.
+	plattEps = 1e-5
+)
+
+/*
+Calibrate will fit the calibration function of forest probabilities using
+`samples`. The samples should not be used when building the forest.
+
+Current supported `method` is CalibrationPlatt.
+
+Algorithm,
+
+(1) Compute the probability of positive class of each sample using forest.
+(2) Fit the sigmoid function, 1/(1+exp(A*p+B)), on the probabilities and the
+actual class of samples, using the Newton method with backtracking line
+search (Lin et al., 2007).
+
+After calibration, the calibrated probabilities can be retrieved using
+PredictCalibrated.
+
+It will return ErrNotBuilt if forest has not been build, ErrNoInput if
+samples is empty, or ErrCalibrationMethod if method is unknown.
+*/
+func (forest *Runtime) Calibrate(method string,
+	samples tabula.ClasetInterface,
+) error {
+	if forest.trainset == nil || len(forest.trees) <= 0 {
+		return ErrNotBuilt
+	}
+	if samples == nil || samples.GetNRow() <= 0 {
+		return ErrNoInput
+	}
+	if method != CalibrationPlatt {
+		return ErrCalibrationMethod
+	}
+
+	vs := forest.trainset.GetClassValueSpace()
+
+	// (1)
+	_, probs := forest.predictSet(samples, nil, vs)
+
+	labels := make([]bool, len(probs))
+	for x, actual := range samples.GetClassAsStrings() {
+		if x >= len(labels) {
+			break
+		}
+		labels[x] = len(vs) > 0 && actual == vs[0]
+	}
+
+	// (2)
+	forest.plattA, forest.plattB = fitPlatt(probs, labels)
+	forest.isCalibrated = true
+
+	return nil
+}
+
+//
+// resetCalibration clear the parameters from Calibrate, so PredictCalibrated
+// return the same value as Predict until the forest is calibrated again.
+//
+func (forest *Runtime) resetCalibration() {
+	forest.isCalibrated = false
+	forest.plattA = 0
+	forest.plattB = 0
+}
+
+//
+// PredictCalibrated will return the class of new `row` and the calibrated
+// probability of each class in value space of training samples.
+// The probability of positive class is calibrated using the sigmoid function
+// from Calibrate, and probabilities of other classes are rescaled so the sum
+// of all probabilities is one.
+//
+// If forest has not been calibrated, it will return the same value as
+// Predict.
+//
+func (forest *Runtime) PredictCalibrated(row *tabula.Row) (
+	class string, probs []float64,
+) {
+	class, probs = forest.Predict(row)
+	if !forest.isCalibrated || len(probs) <= 0 {
+		return class, probs
+	}
+
+	p := plattProb(probs[0], forest.plattA, forest.plattB)
+
+	rest := 1 - probs[0]
+	for x := 1; x < len(probs); x++ {
+		if rest > 0 {
+			probs[x] = probs[x] / rest * (1 - p)
+		} else {
+			probs[x] = (1 - p) / float64(len(probs)-1)
+		}
+	}
+	probs[0] = p
+
+	vs := forest.trainset.GetClassValueSpace()
+
+	idx, ok := forest.selectClass(probs, vs)
+	if ok {
+		class = vs[idx]
+	}
+
+	return class, probs
+}
+
+//
+// plattProb return the value of sigmoid 1/(1+exp(a*f+b)), computed in a way
+// that avoid overflow.
+//
+func plattProb(f, a, b float64) float64 {
+	fApB := f*a + b
+	if fApB >= 0 {
+		return math.Exp(-fApB) / (1 + math.Exp(-fApB))
+	}
+	return 1 / (1 + math.Exp(fApB))
+}
+
+//
+// plattObjective return the negative log-likelihood of sigmoid with
+// parameter `a` and `b` on `scores` and target probabilities `targets`.
+//
+func plattObjective(scores, targets []float64, a, b float64) (fval float64) {
+	for x, f := range scores {
+		fApB := f*a + b
+		if fApB >= 0 {
+			fval += targets[x]*fApB + math.Log(1+math.Exp(-fApB))
+		} else {
+			fval += (targets[x]-1)*fApB + math.Log(1+math.Exp(fApB))
+		}
+	}
+	return
+}
+
+//
+// fitPlatt will fit the sigmoid parameters `a` and `b` on `scores`, where
+// `labels` is true if the sample is positive.
+// The target of each sample is smoothed using the number of positive and
+// negative samples to avoid overfitting.
+//
+func fitPlatt(scores []float64, labels []bool) (a, b float64) {
+	var prior0, prior1 float64
+	for _, label := range labels {
+		if label {
+			prior1++
+		} else {
+			prior0++
+		}
+	}
+
+	hiTarget := (prior1 + 1) / (prior1 + 2)
+	loTarget := 1 / (prior0 + 2)
+
+	targets := make([]float64, len(labels))
+	for x, label := range labels {
+		if label {
+			targets[x] = hiTarget
+		} else {
+			targets[x] = loTarget
+		}
+	}
+
+	a = 0
+	b = math.Log((prior0 + 1) / (prior1 + 1))
+	fval := plattObjective(scores, targets, a, b)
+
+	for iter := 0; iter < plattMaxIter; iter++ {
+		// Compute gradient and Hessian.
+		h11, h22 := plattSigma, plattSigma
+		var h21, g1, g2 float64
+
+		for x, f := range scores {
+			fApB := f*a + b
+
+			var p, q float64
+			if fApB >= 0 {
+				p = math.Exp(-fApB) / (1 + math.Exp(-fApB))
+				q = 1 / (1 + math.Exp(-fApB))
+			} else {
+				p = 1 / (1 + math.Exp(fApB))
+				q = math.Exp(fApB) / (1 + math.Exp(fApB))
+			}
+
+			d2 := p * q
+			h11 += f * f * d2
+			h22 += d2
+			h21 += f * d2
+
+			d1 := targets[x] - p
+			g1 += f * d1
+			g2 += d1
+		}
+
+		if math.Abs(g1) < plattEps && math.Abs(g2) < plattEps {
+			break
+		}
+
+		// Compute Newton direction.
+		det := h11*h22 - h21*h21
+		dA := -(h22*g1 - h21*g2) / det
+		dB := -(-h21*g1 + h11*g2) / det
+		gd := g1*dA + g2*dB
+
+		// Backtracking line search.
+		step := 1.0
+		for step >= plattMinStep {
+			newA := a + step*dA
+			newB := b + step*dB
+			newf := plattObjective(scores, targets, newA, newB)
+
+			if newf < fval+0.0001*step*gd {
+				a, b, fval = newA, newB, newf
+				break
+			}
+			step /= 2
+		}
+
+		if step < plattMinStep {
+			break
+		}
+	}
+
+	return a, b
+}
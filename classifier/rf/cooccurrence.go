@@ -0,0 +1,112 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rf
+
+import (
+	"github.com/shuLhan/go-mining/classifier/cart"
+	"github.com/shuLhan/go-mining/tree/binary"
+)
+
+//
+// SplitCooccurrence return matrix of number of root-to-leaf paths, on all
+// trees in forest, where pair of features is used together for splitting.
+// The matrix is indexed by column index of feature in training samples, so
+// value at [i][j] is equal to [j][i], and value at [i][i] is the number of
+// paths where feature i is used.
+//
+// Feature which is used more than once on the same path is counted once.
+//
+func (forest *Runtime) SplitCooccurrence() (cooc [][]int) {
+	ncol := 0
+	if forest.trainset != nil {
+		ncol = forest.trainset.GetNColumn()
+	}
+
+	// Make sure all split index fit in matrix.
+	for x := range forest.trees {
+		n := maxSplitIdx(forest.trees[x].Tree.Root) + 1
+		if n > ncol {
+			ncol = n
+		}
+	}
+
+	cooc = make([][]int, ncol)
+	for x := range cooc {
+		cooc[x] = make([]int, ncol)
+	}
+
+	for x := range forest.trees {
+		countCooccurrence(cooc, forest.trees[x].Tree.Root, nil)
+	}
+
+	return cooc
+}
+
+//
+// maxSplitIdx return the maximum split attribute index in tree with root
+// `node`, or -1 if the tree does not have any split.
+//
+func maxSplitIdx(node *binary.BTNode) (max int) {
+	max = -1
+	if node == nil {
+		return
+	}
+
+	nodev := node.Value.(cart.NodeValue)
+	if nodev.IsLeaf {
+		return
+	}
+
+	max = nodev.SplitAttrIdx
+
+	if v := maxSplitIdx(node.Left); v > max {
+		max = v
+	}
+	if v := maxSplitIdx(node.Right); v > max {
+		max = v
+	}
+
+	return max
+}
+
+//
+// countCooccurrence will walk the tree from `node` and, at each leaf, add
+// every pair of feature in `path` to `cooc`.
+//
+func countCooccurrence(cooc [][]int, node *binary.BTNode, path []int) {
+	if node == nil {
+		return
+	}
+
+	nodev := node.Value.(cart.NodeValue)
+
+	if nodev.IsLeaf {
+		for _, i := range path {
+			for _, j := range path {
+				cooc[i][j]++
+			}
+		}
+		return
+	}
+
+	found := false
+	for _, idx := range path {
+		if idx == nodev.SplitAttrIdx {
+			found = true
+			break
+		}
+	}
+	if !found {
+		path = append(path, nodev.SplitAttrIdx)
+	}
+
+	// Copy the path, so the left and right branch does not share the
+	// same backing array.
+	left := make([]int, len(path))
+	copy(left, path)
+
+	countCooccurrence(cooc, node.Left, left)
+	countCooccurrence(cooc, node.Right, path)
+}
@@ -0,0 +1,36 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rf
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+//
+// DumpTrees will write each tree in forest in Graphviz DOT format to file
+// "tree_<n>.dot" in directory `dir`, where n is the index of tree in forest.
+// The directory will be created if its not exist.
+//
+func (forest *Runtime) DumpTrees(dir string) (e error) {
+	e = os.MkdirAll(dir, 0755)
+	if e != nil {
+		return
+	}
+
+	for x := range forest.trees {
+		fout := filepath.Join(dir, fmt.Sprintf("tree_%d.dot", x))
+
+		e = ioutil.WriteFile(fout, []byte(forest.trees[x].ToDOT()),
+			0644)
+		if e != nil {
+			return
+		}
+	}
+
+	return nil
+}
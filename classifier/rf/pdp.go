@@ -0,0 +1,66 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rf
+
+import (
+	"github.com/shuLhan/tabula"
+)
+
+/*
+PartialDependence compute the partial dependence of the positive class (the
+first class in value space of training samples) on feature at index
+`featureIdx`, for each value in `grid`.
+
+Algorithm,
+
+(1) For each value in grid,
+(1.1) set the feature in all samples to the grid value,
+(1.2) predict each sample using forest, and
+(1.3) average the probability of positive class on all samples.
+
+The original samples is not modified.
+It will return nil if forest has not been build, samples is empty, or
+`featureIdx` is not a valid feature index.
+*/
+func (forest *Runtime) PartialDependence(featureIdx int, grid []float64,
+	samples tabula.ClasetInterface,
+) (pd []float64) {
+	if forest.trainset == nil || len(forest.trees) <= 0 {
+		return nil
+	}
+	if featureIdx < 0 || featureIdx >= samples.GetNColumn() ||
+		featureIdx == samples.GetClassIndex() {
+		return nil
+	}
+
+	rows := samples.GetDataAsRows()
+	if rows.Len() <= 0 {
+		return nil
+	}
+
+	pd = make([]float64, len(grid))
+
+	// (1)
+	for x, v := range grid {
+		sum := 0.0
+
+		for _, row := range *rows {
+			// (1.1)
+			r := row.Clone()
+			(*r)[featureIdx].SetFloat(v)
+
+			// (1.2)
+			_, probs := forest.Predict(r)
+			if len(probs) > 0 {
+				sum += probs[0]
+			}
+		}
+
+		// (1.3)
+		pd[x] = sum / float64(rows.Len())
+	}
+
+	return pd
+}
@@ -0,0 +1,83 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rf
+
+import (
+	"github.com/shuLhan/tabula"
+)
+
+const (
+	// ColumnPrediction name of column that contain the predicted class in
+	// output of PredictDataset.
+	ColumnPrediction = "prediction"
+	// ColumnProbability name of column that contain the probability of
+	// positive class in output of PredictDataset.
+	ColumnProbability = "probability"
+)
+
+/*
+PredictDataset will predict each row in `samples` and return a copy of
+samples, in rows mode, with two additional columns: "prediction" which
+contain the predicted class, and "probability" which contain the
+probability of positive class (the first class in value space of training
+samples). The result can be written directly using dsv.Writer.
+
+Algorithm,
+
+(1) Create new dataset with the same columns as samples plus prediction and
+probability column.
+(2) For each row in samples,
+(2.1) predict the row using forest,
+(2.2) copy the row and append the prediction and probability, and
+(2.3) push the new row to dataset.
+
+It will return ErrNotBuilt if forest has not been build, or ErrNoInput if
+samples is empty.
+*/
+func (forest *Runtime) PredictDataset(samples tabula.ClasetInterface) (
+	*tabula.Dataset, error,
+) {
+	if forest.trainset == nil || len(forest.trees) <= 0 {
+		return nil, ErrNotBuilt
+	}
+	if samples == nil {
+		return nil, ErrNoInput
+	}
+
+	rows := samples.GetDataAsRows()
+	if rows.Len() <= 0 {
+		return nil, ErrNoInput
+	}
+
+	// (1)
+	types := samples.GetColumnsType()
+	types = append(types, tabula.TString, tabula.TReal)
+
+	names := samples.GetColumnsName()
+	names = append(names, ColumnPrediction, ColumnProbability)
+
+	out := tabula.NewDataset(tabula.DatasetModeRows, types, names)
+
+	// (2)
+	for _, row := range *rows {
+		// (2.1)
+		class, probs := forest.Predict(row)
+
+		prob := 0.0
+		if len(probs) > 0 {
+			prob = probs[0]
+		}
+
+		// (2.2)
+		newRow := row.Clone()
+		newRow.PushBack(tabula.NewRecordString(class))
+		newRow.PushBack(tabula.NewRecordReal(prob))
+
+		// (2.3)
+		out.PushRow(newRow)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,335 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rf
+
+import (
+	"github.com/shuLhan/go-mining/tree/binary"
+	"math"
+	"sort"
+)
+
+const (
+	// minSwapGain is the minimum decrease of total distance to swap the
+	// medoid, to prevent swapping back and forth because of rounding
+	// error.
+	minSwapGain = 1e-9
+)
+
+/*
+Proximity return the proximity matrix of training samples. Proximity between
+sample i and j is the fraction of trees in forest where both samples end up
+in the same leaf node. The proximity of sample with itself is one.
+
+It will return nil if forest has not been build.
+*/
+func (forest *Runtime) Proximity() (prox [][]float64) {
+	if forest.trainset == nil || len(forest.trees) <= 0 {
+		return nil
+	}
+
+	rows := forest.trainset.GetRows()
+	nrow := len(*rows)
+
+	prox = make([][]float64, nrow)
+	for x := range prox {
+		prox[x] = make([]float64, nrow)
+	}
+
+	for x := range forest.trees {
+		leaves := make(map[*binary.BTNode][]int)
+
+		for y, row := range *rows {
+			leaf := forest.trees[x].LeafNode(row)
+			leaves[leaf] = append(leaves[leaf], y)
+		}
+
+		for _, ids := range leaves {
+			for _, a := range ids {
+				for _, b := range ids {
+					prox[a][b]++
+				}
+			}
+		}
+	}
+
+	ntree := float64(len(forest.trees))
+	for x := range prox {
+		for y := range prox[x] {
+			prox[x][y] /= ntree
+		}
+	}
+
+	return prox
+}
+
+/*
+ClusterByProximity will partition the training samples into `k` clusters
+using k-medoids (PAM) with distance one minus proximity, and return the
+cluster label, from 0 to k-1, of each sample.
+
+Algorithm,
+
+(1) Compute the distance matrix from proximity.
+(2) BUILD: select the first medoid that minimize the total distance to all
+samples, and then greedily add a medoid that decrease the total distance
+the most, until we have `k` medoids.
+(3) SWAP: using the distance of each sample to its nearest and second
+nearest medoid, compute the change of total distance when swapping each pair
+of medoid and non-medoid, and do the swap that decrease the total distance
+the most. Repeat until no swap decrease the total distance.
+(4) Assign each sample to its nearest medoid.
+
+Each SWAP iteration take O(k*n^2) time, and the distance matrix take O(n^2)
+memory, where n is the number of training samples, so it is only suitable
+for training samples with several thousands rows.
+
+It will return nil if forest has not been build or `k` is less or equal to
+zero.
+*/
+func (forest *Runtime) ClusterByProximity(k int) (labels []int) {
+	prox := forest.Proximity()
+	if prox == nil || k <= 0 {
+		return nil
+	}
+
+	n := len(prox)
+	if k > n {
+		k = n
+	}
+
+	// (1)
+	dist := make([][]float64, n)
+	for x := range prox {
+		dist[x] = make([]float64, n)
+		for y := range prox[x] {
+			dist[x][y] = 1 - prox[x][y]
+		}
+	}
+
+	// (2)
+	medoids := make([]int, 0, k)
+	isMedoid := make([]bool, n)
+	dnear := make([]float64, n)
+
+	for len(medoids) < k {
+		best := -1
+		bestCost := math.Inf(1)
+
+		for x := 0; x < n; x++ {
+			if isMedoid[x] {
+				continue
+			}
+
+			cost := 0.0
+			for y := 0; y < n; y++ {
+				if len(medoids) == 0 || dist[y][x] < dnear[y] {
+					cost += dist[y][x]
+				} else {
+					cost += dnear[y]
+				}
+			}
+
+			if cost < bestCost {
+				best = x
+				bestCost = cost
+			}
+		}
+
+		medoids = append(medoids, best)
+		isMedoid[best] = true
+
+		for y := 0; y < n; y++ {
+			if len(medoids) == 1 || dist[y][best] < dnear[y] {
+				dnear[y] = dist[y][best]
+			}
+		}
+	}
+
+	// (3)
+	nearest, dnear, dsecond := nearestMedoids(dist, medoids)
+
+	for {
+		bestM, bestX := -1, -1
+		bestDelta := -minSwapGain
+
+		for m := range medoids {
+			for x := 0; x < n; x++ {
+				if isMedoid[x] {
+					continue
+				}
+
+				delta := swapDelta(dist, m, x, nearest, dnear,
+					dsecond)
+				if delta < bestDelta {
+					bestM, bestX = m, x
+					bestDelta = delta
+				}
+			}
+		}
+
+		if bestM < 0 {
+			break
+		}
+
+		isMedoid[medoids[bestM]] = false
+		isMedoid[bestX] = true
+		medoids[bestM] = bestX
+
+		nearest, dnear, dsecond = nearestMedoids(dist, medoids)
+	}
+
+	// (4)
+	return nearest
+}
+
+//
+// nearestMedoids return the index in `medoids` of nearest medoid of each
+// sample, their distance, and the distance to the second nearest medoid.
+// If there is only one medoid, the distance to second nearest medoid is
+// infinity.
+//
+func nearestMedoids(dist [][]float64, medoids []int) (
+	nearest []int, dnear, dsecond []float64,
+) {
+	n := len(dist)
+	nearest = make([]int, n)
+	dnear = make([]float64, n)
+	dsecond = make([]float64, n)
+
+	for x := 0; x < n; x++ {
+		dnear[x] = math.Inf(1)
+		dsecond[x] = math.Inf(1)
+
+		for y, m := range medoids {
+			d := dist[x][m]
+			if d < dnear[x] {
+				nearest[x] = y
+				dsecond[x] = dnear[x]
+				dnear[x] = d
+			} else if d < dsecond[x] {
+				dsecond[x] = d
+			}
+		}
+	}
+
+	return nearest, dnear, dsecond
+}
+
+//
+// swapDelta return the change of total distance when medoid at index `m` is
+// replaced by sample `x`. Sample that is nearest to medoid `m` will move to
+// `x` or to their second nearest medoid, and other samples will move to `x`
+// only if its nearer than their nearest medoid.
+//
+func swapDelta(dist [][]float64, m, x int, nearest []int,
+	dnear, dsecond []float64,
+) (delta float64) {
+	for y := range dist {
+		d := dist[y][x]
+
+		if nearest[y] == m {
+			if d < dsecond[y] {
+				delta += d - dnear[y]
+			} else {
+				delta += dsecond[y] - dnear[y]
+			}
+		} else if d < dnear[y] {
+			delta += d - dnear[y]
+		}
+	}
+	return delta
+}
+
+/*
+OutlierScores return the outlier measure of each training samples, as
+proposed by Breiman. Sample that has low proximity to other samples in the
+same class will have high score. Score greater than 10 is a good indicator
+of outlier.
+
+Algorithm,
+
+(1) For each sample i, compute the raw measure as number of samples divided
+by sum of squared proximity between i and other samples in the same class.
+If sample i never share a leaf with other samples in the same class, the
+minimum non-zero proximity (one over number of tree) is used.
+(2) In each class, standardize the raw measure by subtracting its median
+and dividing by its median absolute deviation.
+
+It will return nil if forest has not been build.
+*/
+func (forest *Runtime) OutlierScores() (scores []float64) {
+	prox := forest.Proximity()
+	if prox == nil {
+		return nil
+	}
+
+	n := len(prox)
+	classes := forest.trainset.GetClassAsStrings()
+	minProx := 1 / float64(len(forest.trees))
+
+	// (1)
+	scores = make([]float64, n)
+	classIds := make(map[string][]int)
+
+	for x := 0; x < n; x++ {
+		classIds[classes[x]] = append(classIds[classes[x]], x)
+	}
+
+	for _, ids := range classIds {
+		for _, x := range ids {
+			sum := 0.0
+			for _, y := range ids {
+				if x != y {
+					sum += prox[x][y] * prox[x][y]
+				}
+			}
+			if sum <= 0 {
+				sum = minProx * minProx
+			}
+			scores[x] = float64(n) / sum
+		}
+	}
+
+	// (2)
+	for _, ids := range classIds {
+		raws := make([]float64, len(ids))
+		for x, id := range ids {
+			raws[x] = scores[id]
+		}
+
+		med := median(raws)
+		for x := range raws {
+			raws[x] = math.Abs(raws[x] - med)
+		}
+		mad := median(raws)
+
+		for _, id := range ids {
+			scores[id] -= med
+			if mad > 0 {
+				scores[id] /= mad
+			}
+		}
+	}
+
+	return scores
+}
+
+//
+// median return the median value of `values`. The order of `values` will be
+// changed.
+//
+func median(values []float64) float64 {
+	if len(values) <= 0 {
+		return 0
+	}
+
+	sort.Float64s(values)
+
+	mid := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[mid]
+	}
+	return (values[mid-1] + values[mid]) / 2
+}
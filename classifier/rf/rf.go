@@ -13,6 +13,7 @@ The implementation is based on various sources and using author experience.
 package rf
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/shuLhan/go-mining/classifier"
@@ -21,8 +22,12 @@ import (
 	"github.com/shuLhan/tabula"
 	"github.com/shuLhan/tekstus"
 	"math"
+	"math/rand"
 	"os"
+	"runtime"
 	"strconv"
+	"sync"
+	"time"
 )
 
 const (
@@ -43,6 +48,35 @@ const (
 
 	// DefStatFile default statistic file.
 	DefStatFile = "rf.stat"
+
+	// maxRetryFactor is the maximum number of retry, relative to number
+	// of tree to grow, when the bootstrap samples contain only one
+	// class.
+	maxRetryFactor = 10
+)
+
+const (
+	// TieBreakFirst if defined in Runtime, the class that come first in
+	// value space will be selected when two or more classes have the
+	// same number of votes.
+	//
+	// This option is used in Runtime.TieBreak.
+	TieBreakFirst = "first"
+
+	// TieBreakMajority if defined in Runtime, the majority class of
+	// training samples will be selected when its one of the classes with
+	// the same number of votes, otherwise the one that come first in
+	// value space.
+	//
+	// This option is used in Runtime.TieBreak, and its the default.
+	TieBreakMajority = "majority"
+
+	// TieBreakRandom if defined in Runtime, one of the classes with the
+	// same number of votes will be selected randomly, using
+	// TieBreakSeed.
+	//
+	// This option is used in Runtime.TieBreak.
+	TieBreakRandom = "random"
 )
 
 var (
@@ -53,6 +87,24 @@ var (
 var (
 	// ErrNoInput will tell you when no input is given.
 	ErrNoInput = errors.New("rf: input samples is empty")
+	// ErrSubsampleTooLarge will tell you when bootstraping without
+	// replacement require more samples than the input samples.
+	ErrSubsampleTooLarge = errors.New("rf: number of subsample is greater" +
+		" than number of samples when bootstraping without replacement")
+	// ErrSingleClassBag will tell you when the bootstrap samples only
+	// contain one class while the training samples contain more.
+	ErrSingleClassBag = errors.New("rf: bootstrap samples contain only" +
+		" one class")
+	// ErrInvalidSampleWeights will tell you when the length of sample
+	// weights is not equal with number of samples, contain negative
+	// weight, all weights is zero, or samples with positive weight
+	// contain only one class while the training samples contain more.
+	ErrInvalidSampleWeights = errors.New("rf: invalid sample weights")
+	// ErrNotBuilt will tell you when the forest need to be build first.
+	ErrNotBuilt = errors.New("rf: forest has not been build")
+	// ErrCalibrationMethod will tell you when the calibration method is
+	// unknown.
+	ErrCalibrationMethod = errors.New("rf: unknown calibration method")
 )
 
 /*
@@ -66,8 +118,52 @@ type Runtime struct {
 	NTree int `json:"NTree"`
 	// NRandomFeature number of feature randomly selected for each tree.
 	NRandomFeature int `json:"NRandomFeature"`
+	// AutoTuneMtry if its true, NRandomFeature will be selected before
+	// building the forest, from half, one, and two times the square-root
+	// of number of features, whichever minimize the OOB error.
+	AutoTuneMtry bool `json:"AutoTuneMtry"`
+	// Bagging if its true, each tree use all features instead of
+	// NRandomFeature, so each tree only differ by their bootstrap
+	// samples. NRandomFeature is not changed.
+	// This option override NRandomFeature and AutoTuneMtry.
+	Bagging bool `json:"Bagging"`
+	// ExtraTrees if its true, each tree is build using random split
+	// value on continuous attribute (see cart.Runtime.ExtraTrees).
+	ExtraTrees bool `json:"ExtraTrees"`
+	// FeatureWeights contain weight for each column in dataset, used to
+	// select the random features in each tree (see
+	// cart.Runtime.FeatureWeights).
+	FeatureWeights []float64 `json:"FeatureWeights"`
 	// PercentBoot percentage of sample for bootstraping.
 	PercentBoot int `json:"PercentBoot"`
+	// BootFraction fraction of sample for bootstraping (e.g. 0.632).
+	// If its greater than zero, it will override PercentBoot.
+	BootFraction float64 `json:"BootFraction"`
+	// Replacement if its false then each tree will be bootstraped
+	// without replacement (pasting). If its nil the default is true.
+	Replacement *bool `json:"Replacement"`
+	// SampleWeights if its not empty, each row in bootstrap samples is
+	// drawn with probability proportional to its weight, instead of
+	// uniformly. Row with zero weight will never be selected.
+	// Rows with positive weight must contain at least two classes.
+	SampleWeights []float64 `json:"SampleWeights"`
+	// OnTreeBuilt if its not nil, will be called after each tree has
+	// been grown, with index of tree in forest and their statistic.
+	OnTreeBuilt func(treeIdx int, stat *classifier.Stat) `json:"-"`
+	// TieBreak define how to select the class when two or more classes
+	// have the same number of votes. Default is TieBreakMajority.
+	TieBreak string `json:"TieBreak"`
+	// TieBreakSeed seed for random generator when TieBreak is
+	// TieBreakRandom.
+	TieBreakSeed int64 `json:"TieBreakSeed"`
+	// SoftVote if its true, the probability of each class is computed by
+	// averaging the class distribution on leaf of each tree, instead of
+	// counting the class votes of each tree.
+	SoftVote bool `json:"SoftVote"`
+	// NWorker number of goroutines used to collect votes of samples in
+	// ClassifySet. If its zero, the number of CPU is used. If its one,
+	// samples are classified serially.
+	NWorker int `json:"NWorker"`
 
 	// nSubsample number of samples used for bootstraping.
 	nSubsample int
@@ -76,6 +172,26 @@ type Runtime struct {
 	// bagIndices contain list of index of selected samples at bootstraping
 	// for book-keeping.
 	bagIndices [][]int
+	// trainset contain the samples used to build the forest, for
+	// computing the OOB estimate of the whole forest.
+	trainset tabula.ClasetInterface
+	// majorityClass contain the majority class in training samples.
+	majorityClass string
+	// isSingleClass will be true if training samples contain only one
+	// class.
+	isSingleClass bool
+	// tieRand random generator for TieBreakRandom.
+	tieRand *rand.Rand
+	// lastOOB contain the out-of-bag samples of the last tree.
+	lastOOB tabula.ClasetInterface
+	// isCalibrated will be true if the probabilities has been calibrated
+	// using Calibrate.
+	isCalibrated bool
+	// plattA and plattB contain the parameters of sigmoid function from
+	// Platt scaling.
+	plattA, plattB float64
+	// mtry number of random feature used by each tree.
+	mtry int
 }
 
 func init() {
@@ -93,6 +209,144 @@ func (forest *Runtime) Trees() []cart.Runtime {
 	return forest.trees
 }
 
+//
+// LastOOBSet return the out-of-bag samples, the samples that is not selected
+// at bootstraping, of the last tree in forest. It will return nil if no tree
+// has been grown from samples (e.g. using BuildFromBags).
+//
+func (forest *Runtime) LastOOBSet() tabula.ClasetInterface {
+	return forest.lastOOB
+}
+
+//
+// BuildDurations return the time to build each tree in forest, with
+// nanosecond resolution.
+//
+func (forest *Runtime) BuildDurations() []time.Duration {
+	return forest.OOBStats().Durations()
+}
+
+//
+// MeanLeafCount return the average number of leaf nodes of all trees in
+// forest, or zero if forest has not been build.
+//
+func (forest *Runtime) MeanLeafCount() float64 {
+	if len(forest.trees) <= 0 {
+		return 0
+	}
+
+	total := 0
+	for x := range forest.trees {
+		total += forest.trees[x].LeafCount()
+	}
+
+	return float64(total) / float64(len(forest.trees))
+}
+
+//
+// FeatureImportance return the mean decrease impurity (Gini importance) of
+// each column in training samples. The importance of each tree is
+// normalized so their sum is one, and then averaged over all trees.
+// It will return nil if forest has not been build.
+//
+func (forest *Runtime) FeatureImportance() (importances []float64) {
+	if forest.trainset == nil || len(forest.trees) <= 0 {
+		return nil
+	}
+
+	ncol := forest.trainset.GetNColumn()
+	importances = make([]float64, ncol)
+
+	for x := range forest.trees {
+		treeImps := forest.trees[x].FeatureImportance(ncol)
+
+		sum := 0.0
+		for _, v := range treeImps {
+			sum += v
+		}
+		if sum <= 0 {
+			continue
+		}
+
+		for y, v := range treeImps {
+			importances[y] += v / sum
+		}
+	}
+
+	for x := range importances {
+		importances[x] /= float64(len(forest.trees))
+	}
+
+	return importances
+}
+
+//
+// OOBErrorCI return the binomial confidence interval, with `confidence`
+// level (e.g. 0.95), of the OOB error of the whole forest, using the number
+// of samples in OOB confusion matrix.
+// It will return zero interval if OOB error can not be computed.
+//
+func (forest *Runtime) OOBErrorCI(confidence float64) (low, high float64) {
+	cm := forest.OOBConfusionMatrix()
+	if cm == nil {
+		return 0, 0
+	}
+
+	var n int64
+	for _, count := range cm.ClassCounts() {
+		n += count
+	}
+
+	return classifier.BinomialCI(cm.GetFalseRate(), n, confidence)
+}
+
+/*
+BagIndices return list of index of selected samples for each tree.
+*/
+func (forest *Runtime) BagIndices() [][]int {
+	return forest.bagIndices
+}
+
+/*
+OOBIndices return list of index of samples that is not selected at
+bootstraping (out-of-bag) for each tree.
+*/
+func (forest *Runtime) OOBIndices() (oobIndices [][]int) {
+	if forest.trainset == nil {
+		return nil
+	}
+
+	nrow := forest.trainset.GetNRow()
+
+	oobIndices = make([][]int, len(forest.bagIndices))
+	for x, bagIdx := range forest.bagIndices {
+		inBag := make([]bool, nrow)
+		for _, idx := range bagIdx {
+			if idx >= 0 && idx < nrow {
+				inBag[idx] = true
+			}
+		}
+
+		for idx, in := range inBag {
+			if !in {
+				oobIndices[x] = append(oobIndices[x], idx)
+			}
+		}
+	}
+
+	return oobIndices
+}
+
+//
+// IsReplacement return true if tree will be bootstraped with replacement.
+//
+func (forest *Runtime) IsReplacement() bool {
+	if forest.Replacement == nil {
+		return true
+	}
+	return *forest.Replacement
+}
+
 /*
 AddCartTree add tree to forest
 */
@@ -114,6 +368,10 @@ func (forest *Runtime) AddBagIndex(bagIndex []int) {
 //
 //	number-of-sample * percentage-of-bootstrap
 //
+// or, if BootFraction is greater than zero,
+//
+//	number-of-sample * fraction-of-bootstrap
+//
 //
 func (forest *Runtime) Initialize(samples tabula.ClasetInterface) error {
 	if forest.NTree <= 0 {
@@ -122,11 +380,15 @@ func (forest *Runtime) Initialize(samples tabula.ClasetInterface) error {
 	if forest.PercentBoot <= 0 {
 		forest.PercentBoot = DefPercentBoot
 	}
-	if forest.NRandomFeature <= 0 {
+	if !forest.Bagging && forest.NRandomFeature <= 0 {
 		// Set default value to square-root of features.
 		ncol := samples.GetNColumn() - 1
 		forest.NRandomFeature = int(math.Sqrt(float64(ncol)))
 	}
+	forest.mtry = forest.NRandomFeature
+	if forest.Bagging {
+		forest.mtry = samples.GetNColumn() - 1
+	}
 	if forest.OOBStatsFile == "" {
 		forest.OOBStatsFile = DefOOBStatsFile
 	}
@@ -137,53 +399,157 @@ func (forest *Runtime) Initialize(samples tabula.ClasetInterface) error {
 		forest.StatFile = DefStatFile
 	}
 
-	forest.nSubsample = int(float32(samples.GetNRow()) *
-		(float32(forest.PercentBoot) / 100.0))
+	if forest.BootFraction > 0 {
+		forest.nSubsample = int(float64(samples.GetNRow()) *
+			forest.BootFraction)
+	} else {
+		forest.nSubsample = int(float32(samples.GetNRow()) *
+			(float32(forest.PercentBoot) / 100.0))
+	}
+
+	if !forest.IsReplacement() && forest.nSubsample > samples.GetNRow() {
+		return ErrSubsampleTooLarge
+	}
+
+	if len(forest.SampleWeights) > 0 {
+		npositive, e := checkSampleWeights(forest.SampleWeights,
+			samples.GetClassAsStrings())
+		if e != nil {
+			return e
+		}
+		if !forest.IsReplacement() && forest.nSubsample > npositive {
+			return ErrSubsampleTooLarge
+		}
+	}
+
+	forest.trainset = samples
+
+	switch forest.TieBreak {
+	case TieBreakFirst, TieBreakMajority, TieBreakRandom:
+	default:
+		forest.TieBreak = TieBreakMajority
+	}
+
+	samples.RecountMajorMinor()
+	forest.majorityClass = samples.MajorityClass()
+	forest.isSingleClass, _ = samples.IsInSingleClass()
+	forest.tieRand = rand.New(rand.NewSource(forest.TieBreakSeed))
+
+	// Calibration of the previous build is not valid for new trees.
+	forest.resetCalibration()
 
 	return forest.Runtime.Initialize()
 }
 
+//
+// Reset will close the statistic file if its open, and clear all trees, bag
+// indices, and statistic in forest, so the same runtime can be build again.
+// The configuration (e.g. NTree, PercentBoot) is not changed.
+//
+func (forest *Runtime) Reset() {
+	forest.Runtime.Reset()
+
+	forest.trees = nil
+	forest.bagIndices = nil
+	forest.lastOOB = nil
+	forest.trainset = nil
+	forest.majorityClass = ""
+	forest.isSingleClass = false
+	forest.tieRand = nil
+	forest.resetCalibration()
+}
+
+//
+// String return the summary of forest: the configuration, number of trees
+// that has been build, and the mean of OOB error.
+//
+func (forest *Runtime) String() string {
+	return fmt.Sprintf("{NTree:%d NRandomFeature:%d PercentBoot:%d"+
+		" NTreeBuilt:%d OOBErrorMean:%f}", forest.NTree,
+		forest.NRandomFeature, forest.PercentBoot, len(forest.trees),
+		forest.StatTotal().OobErrorMean)
+}
+
 /*
 Build the forest using samples dataset.
+*/
+func (forest *Runtime) Build(samples tabula.ClasetInterface) (e error) {
+	return forest.BuildCtx(context.Background(), samples)
+}
+
+/*
+BuildCtx build the forest using samples dataset, and stop growing the trees
+when context `ctx` is done.
+If its stopped, the forest contain all trees that has been build and it will
+return the context error.
 
 Algorithm,
 
-(0) Recheck input value: number of tree, percentage bootstrap, etc; and
+(0) Select number of random feature that minimize the OOB error, from half,
+one, and two times the square-root of number of features, if AutoTuneMtry is
+true and Bagging is false.
+Recheck input value: number of tree, percentage bootstrap, etc; and
     Open statistic file output.
 (1) For 0 to NTree,
-(1.1) Create new tree, repeat until all trees has been build.
+(1.1) Stop if context is done,
+(1.2) Create new tree. If the bootstrap samples contain only one class,
+retry with another samples, up to ten times number of tree in total, and
+return ErrSingleClassBag if its still fail. Any other error is returned
+immediately.
 (2) Compute and write total statistic.
 */
-func (forest *Runtime) Build(samples tabula.ClasetInterface) (e error) {
+func (forest *Runtime) BuildCtx(ctx context.Context,
+	samples tabula.ClasetInterface,
+) (e error) {
 	// check input samples
 	if samples == nil {
 		return ErrNoInput
 	}
 
+	if forest.AutoTuneMtry && !forest.Bagging {
+		forest.NRandomFeature, e = forest.autoTuneMtry(samples)
+		if e != nil {
+			return
+		}
+	}
+
 	// (0)
 	e = forest.Initialize(samples)
 	if e != nil {
 		return
 	}
 
+	// Make sure the statistic file is closed on error.
+	defer forest.CloseOOBStatsFile()
+
 	fmt.Println(tag, "Training set    :", samples)
 	fmt.Println(tag, "Sample (one row):", samples.GetRow(0))
 	fmt.Println(tag, "Forest config   :", forest)
 
 	// (1)
+	nretry := 0
+	maxRetry := maxRetryFactor * forest.NTree
+
 	for t := 0; t < forest.NTree; t++ {
+		// (1.1)
+		select {
+		case <-ctx.Done():
+			e = forest.Finalize()
+			if e != nil {
+				return e
+			}
+			return ctx.Err()
+		default:
+		}
+
 		if DEBUG >= 1 {
 			fmt.Println(tag, "tree #", t)
 		}
 
-		// (1.1)
-		for {
-			_, _, e = forest.GrowTree(samples)
-			if e == nil {
-				break
-			}
-
-			fmt.Println(tag, "error:", e)
+		// (1.2)
+		e = forest.growTree(samples, &nretry, maxRetry)
+		if e != nil {
+			return e
 		}
 	}
 
@@ -191,18 +557,132 @@ func (forest *Runtime) Build(samples tabula.ClasetInterface) (e error) {
 	return forest.Finalize()
 }
 
+/*
+GrowMore will add `n` more trees to the forest that has been build before,
+using the same training `samples` that is used in Build.
+The bag indices, OOB statistic, and total statistic will be continued from
+the last tree, so building the forest with N trees and growing M more trees
+result in the same forest as building N+M trees, given the same random seed.
+
+Since the statistic file has been closed by Build, the statistic of new
+trees is not written to file.
+As in Build, it will return ErrSingleClassBag if the bootstrap samples
+contain only one class after ten times `n` retries.
+The calibration from Calibrate is cleared, since the new trees change the
+probabilities.
+*/
+func (forest *Runtime) GrowMore(n int, samples tabula.ClasetInterface) (
+	e error,
+) {
+	if samples == nil {
+		return ErrNoInput
+	}
+	if forest.trainset == nil || len(forest.trees) <= 0 {
+		return ErrNotBuilt
+	}
+
+	nretry := 0
+	maxRetry := maxRetryFactor * n
+
+	for t := 0; t < n; t++ {
+		if DEBUG >= 1 {
+			fmt.Println(tag, "tree #", len(forest.trees))
+		}
+
+		e = forest.growTree(samples, &nretry, maxRetry)
+		if e != nil {
+			return e
+		}
+	}
+
+	if n > 0 {
+		forest.NTree += n
+		forest.resetCalibration()
+	}
+
+	return forest.Finalize()
+}
+
+//
+// BuildFromBags build the forest where each tree is build using one of the
+// sample in `bags`, e.g. from reservoir sampling of the dataset that does not
+// fit in memory. Number of tree will be equal to number of bags.
+//
+// Since the samples that is not used by each tree is unknown, OOB is not
+// computed and OOBConfusionMatrix will return nil.
+// It will return ErrNoInput if one of the bag is nil.
+//
+func (forest *Runtime) BuildFromBags(bags []tabula.ClasetInterface) (e error) {
+	if len(bags) <= 0 {
+		return ErrNoInput
+	}
+
+	forest.NTree = len(bags)
+
+	e = forest.Initialize(bags[0])
+	if e != nil {
+		return
+	}
+
+	// Make sure the statistic file is closed on error.
+	defer forest.CloseOOBStatsFile()
+
+	fmt.Println(tag, "Forest config   :", forest)
+
+	for t, bag := range bags {
+		if bag == nil {
+			return ErrNoInput
+		}
+
+		if DEBUG >= 1 {
+			fmt.Println(tag, "tree #", t)
+		}
+
+		stat := &classifier.Stat{}
+		stat.ID = int64(len(forest.trees))
+		stat.Start()
+
+		tree, e := forest.newTree(bag)
+		if e != nil {
+			return e
+		}
+
+		forest.AddCartTree(*tree)
+
+		stat.End()
+		forest.AddStat(stat)
+		forest.ComputeStatTotal(stat)
+
+		e = forest.WriteOOBStat(stat)
+		if e != nil {
+			return e
+		}
+
+		if forest.OnTreeBuilt != nil {
+			forest.OnTreeBuilt(len(forest.trees)-1, stat)
+		}
+	}
+
+	return forest.Finalize()
+}
+
 /*
 GrowTree build a new tree in forest, return OOB error value or error if tree
 can not grow.
 
 Algorithm,
 
-(1) Select random samples with replacement, also with OOB.
+(1) Select random samples with or without replacement, also with OOB.
+If SampleWeights is set, each sample is selected proportional to its weight.
+(1.1) If samples contain only one class, while training samples is not, return
+ErrSingleClassBag, so the caller can select another samples.
 (2) Build tree using CART, without pruning.
 (3) Add tree to forest.
-(4) Save index of random samples for calculating error rate later.
+(4) Save index of random samples for calculating error rate later, and the
+OOB samples.
 (5) Run OOB on forest.
 (6) Calculate OOB error rate and statistic values.
+(7) Call OnTreeBuilt if its set.
 */
 func (forest *Runtime) GrowTree(samples tabula.ClasetInterface) (
 	cm *classifier.CM, stat *classifier.Stat, e error,
@@ -212,33 +692,52 @@ func (forest *Runtime) GrowTree(samples tabula.ClasetInterface) (
 	stat.Start()
 
 	// (1)
-	bag, oob, bagIdx, oobIdx := tabula.RandomPickRows(
-		samples.(tabula.DatasetInterface),
-		forest.nSubsample, true)
+	var bagset, oobset tabula.ClasetInterface
+	var bagIdx, oobIdx []int
+
+	if len(forest.SampleWeights) > 0 {
+		bagset, oobset, bagIdx, oobIdx = weightedPickRows(samples,
+			forest.nSubsample, forest.SampleWeights,
+			forest.IsReplacement())
+	} else {
+		bag, oob, bIdx, oIdx := tabula.RandomPickRows(
+			samples.(tabula.DatasetInterface),
+			forest.nSubsample, forest.IsReplacement())
+
+		bagset = bag.(tabula.ClasetInterface)
+		oobset = oob.(tabula.ClasetInterface)
+		bagIdx, oobIdx = bIdx, oIdx
+	}
 
-	bagset := bag.(tabula.ClasetInterface)
+	bagset.RecountMajorMinor()
 
 	if DEBUG >= 2 {
-		bagset.RecountMajorMinor()
 		fmt.Println(tag, "Bagging:", bagset)
 	}
 
+	// (1.1)
+	if !forest.isSingleClass {
+		single, _ := bagset.IsInSingleClass()
+		if single {
+			return nil, nil, ErrSingleClassBag
+		}
+	}
+
 	// (2)
-	cart, e := cart.New(bagset, cart.SplitMethodGini,
-		forest.NRandomFeature)
+	tree, e := forest.newTree(bagset)
 	if e != nil {
 		return nil, nil, e
 	}
 
 	// (3)
-	forest.AddCartTree(*cart)
+	forest.AddCartTree(*tree)
 
 	// (4)
 	forest.AddBagIndex(bagIdx)
+	forest.lastOOB = oobset
 
 	// (5)
 	if forest.RunOOB {
-		oobset := oob.(tabula.ClasetInterface)
 		_, cm, _ = forest.ClassifySet(oobset, oobIdx)
 
 		forest.AddOOBCM(cm)
@@ -264,9 +763,60 @@ func (forest *Runtime) GrowTree(samples tabula.ClasetInterface) (
 	forest.ComputeStatTotal(stat)
 	e = forest.WriteOOBStat(stat)
 
+	// (7)
+	if forest.OnTreeBuilt != nil {
+		forest.OnTreeBuilt(len(forest.trees)-1, stat)
+	}
+
 	return cm, stat, e
 }
 
+//
+// growTree will grow one new tree in forest. If the bootstrap samples contain
+// only one class, it will retry with another samples, while the total number
+// of retry, `nretry`, is less than `maxRetry`. Any other error, including
+// error when writing the statistic after the tree has been added to forest,
+// is returned immediately without retry.
+//
+func (forest *Runtime) growTree(samples tabula.ClasetInterface, nretry *int,
+	maxRetry int,
+) (e error) {
+	for {
+		_, _, e = forest.GrowTree(samples)
+		if e != ErrSingleClassBag {
+			return e
+		}
+		if *nretry >= maxRetry {
+			return e
+		}
+		*nretry++
+
+		fmt.Println(tag, "error:", e)
+	}
+}
+
+//
+// newTree will build and return new CART tree using `bag` samples and
+// forest configuration.
+//
+func (forest *Runtime) newTree(bag tabula.ClasetInterface) (
+	tree *cart.Runtime, e error,
+) {
+	tree = &cart.Runtime{
+		SplitMethod:    cart.SplitMethodGini,
+		NRandomFeature: forest.mtry,
+		ExtraTrees:     forest.ExtraTrees,
+		FeatureWeights: forest.FeatureWeights,
+	}
+
+	e = tree.Build(bag)
+	if e != nil {
+		return nil, e
+	}
+
+	return tree, nil
+}
+
 //
 // ClassifySet given a samples predict their class by running each sample in
 // forest, adn return their class prediction with confusion matrix.
@@ -278,10 +828,8 @@ func (forest *Runtime) GrowTree(samples tabula.ClasetInterface) (
 // Algorithm,
 //
 // (0) Get value space (possible class values in dataset)
-// (1) For each row in test-set,
-// (1.1) collect votes in all trees,
-// (1.2) select majority class vote, and
-// (1.3) compute and save the actual class probabilities.
+// (1) Collect votes in all trees for each row in test-set, concurrently
+// using NWorker goroutines, and select the class with maximum probability.
 // (2) Compute confusion matrix from predictions.
 // (3) Compute stat from confusion matrix.
 // (4) Write the stat to file only if sampleIds is empty, which mean its run
@@ -304,29 +852,9 @@ func (forest *Runtime) ClassifySet(samples tabula.ClasetInterface,
 	// (0)
 	vs := samples.GetClassValueSpace()
 	actuals := samples.GetClassAsStrings()
-	sampleIdx := -1
 
 	// (1)
-	rows := samples.GetRows()
-	for x, row := range *rows {
-		// (1.1)
-		if len(sampleIds) > 0 {
-			sampleIdx = sampleIds[x]
-		}
-		votes := forest.Votes(row, sampleIdx)
-
-		// (1.2)
-		classProbs := tekstus.WordsProbabilitiesOf(votes, vs, false)
-
-		_, idx, ok := numerus.Floats64FindMax(classProbs)
-
-		if ok {
-			predicts = append(predicts, vs[idx])
-		}
-
-		// (1.3)
-		probs = append(probs, classProbs[0])
-	}
+	predicts, probs = forest.predictSet(samples, sampleIds, vs)
 
 	// (2)
 	cm = forest.ComputeCM(sampleIds, vs, actuals, predicts)
@@ -335,6 +863,7 @@ func (forest *Runtime) ClassifySet(samples tabula.ClasetInterface,
 	forest.ComputeStatFromCM(&stat, cm)
 	stat.End()
 
+	// (4)
 	if len(sampleIds) <= 0 {
 		fmt.Println(tag, "CM:", cm)
 		fmt.Println(tag, "Classifying stat:", stat)
@@ -344,6 +873,181 @@ func (forest *Runtime) ClassifySet(samples tabula.ClasetInterface,
 	return predicts, cm, probs
 }
 
+//
+// PredictSet will predict the class of each row in `samples` and return
+// their class prediction and probability of positive class, without
+// computing confusion matrix and statistic. This is lighter than
+// ClassifySet when only the predictions is needed, for example to compute
+// accuracy using classifier.AccuracyOf.
+//
+func (forest *Runtime) PredictSet(samples tabula.ClasetInterface) (
+	predicts []string, probs []float64,
+) {
+	return forest.predictSet(samples, nil, samples.GetClassValueSpace())
+}
+
+//
+// predictSet will collect votes of each row in `samples`, select the class
+// with maximum probability, and return the predicted class and
+// probability of positive class in `vs` for each row.
+//
+func (forest *Runtime) predictSet(samples tabula.ClasetInterface,
+	sampleIds []int, vs []string,
+) (
+	predicts []string, probs []float64,
+) {
+	rows := samples.GetRows()
+	rowsProbs := forest.classProbs(rows, sampleIds, vs)
+
+	predicts = make([]string, 0, len(*rows))
+	probs = make([]float64, 0, len(*rows))
+
+	for _, classProbs := range rowsProbs {
+		idx, ok := forest.selectClass(classProbs, vs)
+
+		if ok {
+			predicts = append(predicts, vs[idx])
+		}
+
+		probs = append(probs, classProbs[0])
+	}
+
+	return predicts, probs
+}
+
+//
+// classProbs will collect votes of each row in `rows` from all trees and
+// return the probabilities of each class in `vs`, in the same order as rows.
+// The rows is divided between NWorker goroutines, where each goroutine
+// write the result directly into their own index, so no locking is needed.
+// If `sampleIds` is not empty, the vote of tree that use the sample for
+// training is not counted.
+//
+func (forest *Runtime) classProbs(rows *tabula.Rows, sampleIds []int,
+	vs []string,
+) (rowsProbs [][]float64) {
+	rowsProbs = make([][]float64, len(*rows))
+
+	compute := func(x int) {
+		sampleIdx := -1
+		if len(sampleIds) > 0 {
+			sampleIdx = sampleIds[x]
+		}
+
+		rowsProbs[x], _ = forest.probabilities((*rows)[x], sampleIdx, vs)
+	}
+
+	nworker := forest.NWorker
+	if nworker <= 0 {
+		nworker = runtime.NumCPU()
+	}
+	if nworker > len(*rows) {
+		nworker = len(*rows)
+	}
+
+	if nworker <= 1 {
+		for x := range *rows {
+			compute(x)
+		}
+		return
+	}
+
+	jobs := make(chan int, len(*rows))
+	for x := range *rows {
+		jobs <- x
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < nworker; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for x := range jobs {
+				compute(x)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return
+}
+
+//
+// Predict will return the majority class of new `row` by collecting votes
+// from all trees in forest, and the probability of each class in value space
+// of training samples.
+// It will return empty class and nil probabilities if forest has not been
+// build.
+//
+func (forest *Runtime) Predict(row *tabula.Row) (class string, probs []float64) {
+	if forest.trainset == nil || len(forest.trees) <= 0 {
+		return "", nil
+	}
+
+	vs := forest.trainset.GetClassValueSpace()
+
+	probs, _ = forest.probabilities(row, -1, vs)
+
+	idx, ok := forest.selectClass(probs, vs)
+	if ok {
+		class = vs[idx]
+	}
+
+	return class, probs
+}
+
+//
+// Classify return the majority class of `row` from all trees in forest.
+//
+func (forest *Runtime) Classify(row *tabula.Row) (class string) {
+	class, _ = forest.Predict(row)
+	return class
+}
+
+//
+// selectClass return the index of class in value space `vs` that have the
+// maximum probability. If two or more classes have the same probability,
+// the class is selected based on TieBreak.
+//
+func (forest *Runtime) selectClass(probs []float64, vs []string) (
+	idx int, ok bool,
+) {
+	max, idx, ok := numerus.Floats64FindMax(probs)
+	if !ok {
+		return idx, ok
+	}
+
+	var ties []int
+	for x, p := range probs {
+		if p == max {
+			ties = append(ties, x)
+		}
+	}
+	if len(ties) <= 1 {
+		return idx, ok
+	}
+
+	switch forest.TieBreak {
+	case TieBreakMajority:
+		for _, x := range ties {
+			if vs[x] == forest.majorityClass {
+				return x, ok
+			}
+		}
+	case TieBreakRandom:
+		if forest.tieRand == nil {
+			forest.tieRand = rand.New(rand.NewSource(
+				forest.TieBreakSeed))
+		}
+		return ties[forest.tieRand.Intn(len(ties))], ok
+	}
+
+	return ties[0], ok
+}
+
 //
 // Votes will return votes, or classes, in each tree based on sample.
 // If checkIdx is true then the `sampleIdx` will be checked in if it has been used
@@ -358,12 +1062,8 @@ func (forest *Runtime) Votes(sample *tabula.Row, sampleIdx int) (
 ) {
 	for x, tree := range forest.trees {
 		// (1)
-		if sampleIdx >= 0 {
-			exist := numerus.IntsIsExist(forest.bagIndices[x],
-				sampleIdx)
-			if exist {
-				continue
-			}
+		if forest.isInBag(x, sampleIdx) {
+			continue
 		}
 
 		// (2)
@@ -374,3 +1074,133 @@ func (forest *Runtime) Votes(sample *tabula.Row, sampleIdx int) (
 	}
 	return votes
 }
+
+//
+// isInBag will return true if sample at index `sampleIdx` is used to build
+// the tree at index `treeIdx`. If `sampleIdx` is negative, it will always
+// return false.
+//
+func (forest *Runtime) isInBag(treeIdx, sampleIdx int) bool {
+	if sampleIdx < 0 || treeIdx >= len(forest.bagIndices) {
+		return false
+	}
+	return numerus.IntsIsExist(forest.bagIndices[treeIdx], sampleIdx)
+}
+
+//
+// probabilities will return the probability of each class in `vs` for
+// `sample` and number of trees that vote. The vote of tree that use sample
+// at index `sampleIdx` for training is not counted.
+//
+// If SoftVote is false, the probability of class is the number of trees
+// that vote for the class divided by number of trees that vote.
+// If SoftVote is true, the probability of class is the average of class
+// proportion on leaf where sample is classified in each tree. Tree with
+// empty leaf vote with their leaf class.
+//
+func (forest *Runtime) probabilities(sample *tabula.Row, sampleIdx int,
+	vs []string,
+) (
+	probs []float64, nvote int,
+) {
+	if !forest.SoftVote {
+		votes := forest.Votes(sample, sampleIdx)
+		probs = tekstus.WordsProbabilitiesOf(votes, vs, false)
+		return probs, len(votes)
+	}
+
+	probs = make([]float64, len(vs))
+
+	for x, tree := range forest.trees {
+		if forest.isInBag(x, sampleIdx) {
+			continue
+		}
+
+		nvote++
+
+		dist := tree.ClassifyDistribution(sample)
+
+		total := 0
+		for _, n := range dist {
+			total += n
+		}
+
+		if total <= 0 {
+			class := tree.Classify(sample)
+			for y, v := range vs {
+				if v == class {
+					probs[y]++
+				}
+			}
+			continue
+		}
+
+		for y, v := range vs {
+			probs[y] += float64(dist[v]) / float64(total)
+		}
+	}
+
+	if nvote > 0 {
+		for y := range probs {
+			probs[y] /= float64(nvote)
+		}
+	}
+
+	return probs, nvote
+}
+
+//
+// OOBConfusionMatrix return the confusion matrix of the whole forest on
+// training samples, where each sample is classified only by the trees that
+// does not use the sample in their bootstrap.
+// Sample that is used by all trees is not counted.
+// It will return nil if forest has not been build, or build without bootstrap
+// indices (see BuildFromBags).
+//
+// Algorithm,
+//
+// (1) For each row in training samples,
+// (1.1) collect votes in trees where the row is out-of-bag,
+// (1.2) skip row if no tree vote,
+// (1.3) select majority class vote.
+// (2) Compute confusion matrix from predictions.
+//
+func (forest *Runtime) OOBConfusionMatrix() *classifier.CM {
+	if forest.trainset == nil || len(forest.trees) <= 0 {
+		return nil
+	}
+	if len(forest.bagIndices) != len(forest.trees) {
+		return nil
+	}
+
+	vs := forest.trainset.GetClassValueSpace()
+	classes := forest.trainset.GetClassAsStrings()
+
+	var sampleIds []int
+	var actuals, predicts []string
+
+	// (1)
+	rows := forest.trainset.GetRows()
+	for x, row := range *rows {
+		// (1.1)
+		classProbs, nvote := forest.probabilities(row, x, vs)
+
+		// (1.2)
+		if nvote <= 0 {
+			continue
+		}
+
+		// (1.3)
+		idx, ok := forest.selectClass(classProbs, vs)
+		if !ok {
+			continue
+		}
+
+		sampleIds = append(sampleIds, x)
+		actuals = append(actuals, classes[x])
+		predicts = append(predicts, vs[idx])
+	}
+
+	// (2)
+	return forest.ComputeCM(sampleIds, vs, actuals, predicts)
+}
@@ -5,6 +5,10 @@
 package rf_test
 
 import (
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/go-mining/classifier/rf"
+	"github.com/shuLhan/tabula"
 	"testing"
 )
 
@@ -20,3 +24,81 @@ func BenchmarkPhoneme(b *testing.B) {
 		runRandomForest()
 	}
 }
+
+func benchmarkClassifySet(b *testing.B, nworker int) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/phoneme/phoneme.dsv", &samples)
+	if e != nil {
+		b.Fatal(e)
+	}
+
+	forest := rf.Runtime{
+		Runtime: classifier.Runtime{
+			OOBStatsFile: "phoneme.oob",
+		},
+		NTree: 50,
+	}
+
+	e = forest.Build(&samples)
+	if e != nil {
+		b.Fatal(e)
+	}
+
+	forest.NWorker = nworker
+
+	b.ResetTimer()
+
+	for x := 0; x < b.N; x++ {
+		forest.ClassifySet(&samples, nil)
+	}
+}
+
+func BenchmarkClassifySetSerial(b *testing.B) {
+	benchmarkClassifySet(b, 1)
+}
+
+func BenchmarkClassifySetConcurrent(b *testing.B) {
+	benchmarkClassifySet(b, 0)
+}
+
+func benchmarkAccuracy(b *testing.B, withCM bool) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/phoneme/phoneme.dsv", &samples)
+	if e != nil {
+		b.Fatal(e)
+	}
+
+	forest := rf.Runtime{
+		Runtime: classifier.Runtime{
+			OOBStatsFile: "phoneme.oob",
+		},
+		NTree: 20,
+	}
+
+	e = forest.Build(&samples)
+	if e != nil {
+		b.Fatal(e)
+	}
+
+	actuals := samples.GetClassAsStrings()
+
+	b.ResetTimer()
+
+	for x := 0; x < b.N; x++ {
+		if withCM {
+			_, cm, _ := forest.ClassifySet(&samples, nil)
+			_ = cm.GetTrueRate()
+		} else {
+			predicts, _ := forest.PredictSet(&samples)
+			_ = classifier.AccuracyOf(actuals, predicts)
+		}
+	}
+}
+
+func BenchmarkAccuracyWithCM(b *testing.B) {
+	benchmarkAccuracy(b, true)
+}
+
+func BenchmarkAccuracyWithoutCM(b *testing.B) {
+	benchmarkAccuracy(b, false)
+}
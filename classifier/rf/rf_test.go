@@ -5,13 +5,25 @@
 package rf_test
 
 import (
+	"context"
 	"fmt"
 	"github.com/shuLhan/dsv"
 	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/go-mining/classifier/cart"
 	"github.com/shuLhan/go-mining/classifier/rf"
+	"github.com/shuLhan/go-mining/dataset"
 	"github.com/shuLhan/tabula"
+	"io/ioutil"
 	"log"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime/debug"
+	"strings"
 	"testing"
+	"time"
 )
 
 // Global options to run for each test.
@@ -40,6 +52,41 @@ var (
 	StatFile string
 )
 
+func assert(t *testing.T, exp, got interface{}, equal bool) {
+	if reflect.DeepEqual(exp, got) != equal {
+		debug.PrintStack()
+		t.Fatalf("\n"+
+			">>> Expecting '%v'\n"+
+			"          got '%v'\n", exp, got)
+	}
+}
+
+//
+// readIris return the iris samples for testing.
+//
+func readIris(t *testing.T) *tabula.Claset {
+	samples := &tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/iris/iris.dsv", samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+	return samples
+}
+
+//
+// newIrisForest return new forest with `ntree` trees that write their OOB
+// statistic into "iris.oob", and the iris samples to build it.
+//
+func newIrisForest(t *testing.T, ntree int) (*rf.Runtime, *tabula.Claset) {
+	forest := &rf.Runtime{
+		Runtime: classifier.Runtime{
+			OOBStatsFile: "iris.oob",
+		},
+		NTree: ntree,
+	}
+	return forest, readIris(t)
+}
+
 func getSamples() (train, test tabula.ClasetInterface) {
 	samples := tabula.Claset{}
 	_, e := dsv.SimpleRead(SampleDsvFile, &samples)
@@ -51,17 +98,8 @@ func getSamples() (train, test tabula.ClasetInterface) {
 		return &samples, nil
 	}
 
-	ntrain := int(float32(samples.Len()) * (float32(NBootstrap) / 100.0))
-
-	bag, oob, _, _ := tabula.RandomPickRows(&samples, ntrain, false)
-
-	train = bag.(tabula.ClasetInterface)
-	test = oob.(tabula.ClasetInterface)
-
-	train.SetClassIndex(samples.GetClassIndex())
-	test.SetClassIndex(samples.GetClassIndex())
-
-	return train, test
+	return dataset.TrainTestSplit(&samples, float64(NBootstrap)/100.0,
+		time.Now().UnixNano(), false)
 }
 
 func runRandomForest() {
@@ -178,3 +216,1265 @@ func TestWvc2010Lnsmote(t *testing.T) {
 
 	runRandomForest()
 }
+
+func TestOOBConfusionMatrixIris(t *testing.T) {
+	forest, samples := newIrisForest(t, 50)
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	cm := forest.OOBConfusionMatrix()
+
+	fmt.Println("[rf_test] OOB CM:", cm)
+
+	counts := cm.ClassCounts()
+
+	var total int64
+	for _, n := range counts {
+		total += n
+	}
+
+	if total <= 0 || total > int64(samples.GetNRow()) {
+		t.Fatalf("Expecting number of OOB samples in (0,%d], got %d",
+			samples.GetNRow(), total)
+	}
+
+	if cm.GetTrueRate() < 0.8 {
+		t.Fatalf("Expecting OOB true rate >= 0.8, got %f",
+			cm.GetTrueRate())
+	}
+}
+
+func TestPredictIris(t *testing.T) {
+	forest, samples := newIrisForest(t, 20)
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// First sample in iris is "Iris-setosa", which is easily separated
+	// from other classes.
+	row := samples.GetRow(0)
+	exp := samples.GetClassAsStrings()[0]
+
+	class, probs := forest.Predict(row)
+
+	fmt.Println("[rf_test] predict:", class, probs)
+
+	if class != exp {
+		t.Fatalf("Expecting class %s, got %s", exp, class)
+	}
+
+	var sum float64
+	for _, p := range probs {
+		sum += p
+	}
+	if sum < 0.999 || sum > 1.001 {
+		t.Fatalf("Expecting sum of probabilities is 1, got %f", sum)
+	}
+}
+
+func TestBootstrapWithoutReplacement(t *testing.T) {
+	replacement := false
+	forest, samples := newIrisForest(t, 5)
+	forest.Replacement = &replacement
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	for x, bagIdx := range forest.BagIndices() {
+		seen := make(map[int]bool, len(bagIdx))
+		for _, idx := range bagIdx {
+			if seen[idx] {
+				t.Fatalf("Tree %d: duplicate sample index %d in bag",
+					x, idx)
+			}
+			seen[idx] = true
+		}
+	}
+
+	forest.PercentBoot = 110
+	e = forest.Build(samples)
+	if e != rf.ErrSubsampleTooLarge {
+		t.Fatalf("Expecting error %v, got %v", rf.ErrSubsampleTooLarge,
+			e)
+	}
+}
+
+func TestOOBErrorSteps(t *testing.T) {
+	forest, samples := newIrisForest(t, 10)
+	forest.RunOOB = true
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	steps := forest.OOBErrorSteps()
+
+	fmt.Println("[rf_test] OOB error steps:", steps)
+
+	if len(steps) != forest.NTree {
+		t.Fatalf("Expecting %d steps, got %d", forest.NTree, len(steps))
+	}
+
+	stats := *forest.OOBStats()
+	if steps[0] != stats[0].OobError {
+		t.Fatalf("Expecting first step %f, got %f", stats[0].OobError,
+			steps[0])
+	}
+	for _, v := range steps {
+		if v < 0 || v > 1 {
+			t.Fatalf("Expecting OOB error in [0,1], got %f", v)
+		}
+	}
+}
+
+func TestBuildCtxCancel(t *testing.T) {
+	forest, samples := newIrisForest(t, 1000000)
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		500*time.Millisecond)
+	defer cancel()
+
+	e := forest.BuildCtx(ctx, samples)
+	if e != context.DeadlineExceeded {
+		t.Fatalf("Expecting error %v, got %v", context.DeadlineExceeded,
+			e)
+	}
+
+	ntree := len(forest.Trees())
+
+	fmt.Println("[rf_test] number of trees after cancel:", ntree)
+
+	if ntree <= 0 || ntree >= forest.NTree {
+		t.Fatalf("Expecting partial forest, got %d trees", ntree)
+	}
+
+	// Partial forest must still be usable.
+	class, _ := forest.Predict(samples.GetRow(0))
+	if class == "" {
+		t.Fatal("Expecting prediction from partial forest")
+	}
+}
+
+func TestOnTreeBuilt(t *testing.T) {
+	var idxs []int
+
+	forest, samples := newIrisForest(t, 7)
+	forest.OnTreeBuilt = func(treeIdx int, stat *classifier.Stat) {
+		idxs = append(idxs, treeIdx)
+	}
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if len(idxs) != forest.NTree {
+		t.Fatalf("Expecting callback called %d times, got %d",
+			forest.NTree, len(idxs))
+	}
+	for x, idx := range idxs {
+		if idx != x {
+			t.Fatalf("Expecting tree index %d, got %d", x, idx)
+		}
+	}
+}
+
+func TestTieBreak(t *testing.T) {
+	const (
+		classIdx = 4
+		setosa   = "Iris-setosa"
+		versi    = "Iris-versicolor"
+	)
+
+	selectClass := func(class string) tabula.ClasetInterface {
+		ds := tabula.SelectRowsWhere(readIris(t), classIdx, class)
+		set := ds.(tabula.ClasetInterface)
+		set.SetClassIndex(classIdx)
+		return set
+	}
+
+	// Each tree is build from single class, so it always vote for that
+	// class, and each sample will have the same number of votes for
+	// setosa and versicolor.
+	setosaTree, e := cart.New(selectClass(setosa), cart.SplitMethodGini, 0)
+	if e != nil {
+		t.Fatal(e)
+	}
+	versiTree, e := cart.New(selectClass(versi), cart.SplitMethodGini, 0)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// Training samples where versicolor is the majority class.
+	trainset := selectClass(versi)
+	for _, row := range *selectClass(setosa).GetRows() {
+		if trainset.GetNRow() >= 60 {
+			break
+		}
+		trainset.PushRow(row)
+	}
+
+	newForest := func(tieBreak string) *rf.Runtime {
+		forest := &rf.Runtime{
+			Runtime: classifier.Runtime{
+				OOBStatsFile: "iris.oob",
+			},
+			TieBreak:     tieBreak,
+			TieBreakSeed: 1,
+		}
+
+		e := forest.Initialize(trainset)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		// Predict does not write any statistic, close the file that
+		// is opened by Initialize.
+		e = forest.CloseOOBStatsFile()
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		forest.AddCartTree(*setosaTree)
+		forest.AddCartTree(*versiTree)
+
+		return forest
+	}
+
+	row := trainset.GetRow(0)
+
+	class, _ := newForest(rf.TieBreakFirst).Predict(row)
+	assert(t, setosa, class, true)
+
+	class, _ = newForest(rf.TieBreakMajority).Predict(row)
+	assert(t, versi, class, true)
+
+	// Default tie break is the majority class of training samples.
+	class, _ = newForest("").Predict(row)
+	assert(t, versi, class, true)
+
+	forest := newForest(rf.TieBreakRandom)
+	got := make(map[string]int)
+	for x := 0; x < 100; x++ {
+		class, _ = forest.Predict(row)
+		got[class]++
+	}
+
+	fmt.Println("[rf_test] random tie break:", got)
+
+	if got[setosa] == 0 || got[versi] == 0 {
+		t.Fatalf("Expecting both classes selected randomly, got %v",
+			got)
+	}
+}
+
+func TestBagAndOOBIndices(t *testing.T) {
+	forest, samples := newIrisForest(t, 5)
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	nrow := samples.GetNRow()
+	bags := forest.BagIndices()
+	oobs := forest.OOBIndices()
+
+	assert(t, forest.NTree, len(bags), true)
+	assert(t, forest.NTree, len(oobs), true)
+
+	for x := range bags {
+		inBag := make(map[int]bool)
+		for _, idx := range bags[x] {
+			inBag[idx] = true
+		}
+
+		for _, idx := range oobs[x] {
+			if inBag[idx] {
+				t.Fatalf("Tree %d: index %d is in bag and OOB",
+					x, idx)
+			}
+		}
+
+		if len(inBag)+len(oobs[x]) != nrow {
+			t.Fatalf("Tree %d: expecting bag and OOB cover %d"+
+				" samples, got %d", x, nrow,
+				len(inBag)+len(oobs[x]))
+		}
+	}
+}
+
+func TestSingleClassBootstrap(t *testing.T) {
+	iris := readIris(t)
+
+	// Use all setosa and only three versicolor samples, so most of
+	// the small bootstrap samples will contain only one class.
+	samples := iris.Clone().(*tabula.Claset)
+	for x, row := range *iris.GetRows() {
+		if x >= 53 {
+			break
+		}
+		samples.PushRow(row.Clone())
+	}
+
+	forest := rf.Runtime{
+		Runtime: classifier.Runtime{
+			RunOOB: true,
+		},
+		NTree:       20,
+		PercentBoot: 10,
+	}
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	classes := samples.GetClassAsStrings()
+
+	for x, bag := range forest.BagIndices() {
+		uniq := make(map[string]bool)
+		for _, idx := range bag {
+			uniq[classes[idx]] = true
+		}
+		if len(uniq) < 2 {
+			t.Fatalf("Tree %d: bootstrap contain only one class", x)
+		}
+	}
+
+	stat := forest.StatTotal()
+	for _, v := range []float64{stat.OobError, stat.Accuracy} {
+		if math.IsNaN(v) {
+			t.Fatalf("Expecting total statistic is not NaN, got %v",
+				stat)
+		}
+	}
+}
+
+func TestSingleClassBagRetryLimit(t *testing.T) {
+	forest, samples := newIrisForest(t, 5)
+
+	// Bootstrap with only one sample will always contain one class, so
+	// build must stop retrying and return the error.
+	forest.PercentBoot = 1
+
+	e := forest.Build(samples)
+	assert(t, rf.ErrSingleClassBag, e, true)
+}
+
+func TestSplitCooccurrence(t *testing.T) {
+	forest, samples := newIrisForest(t, 10)
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	cooc := forest.SplitCooccurrence()
+
+	fmt.Println("[rf_test] split co-occurrence:", cooc)
+
+	assert(t, samples.GetNColumn(), len(cooc), true)
+
+	nused := 0
+	for i := range cooc {
+		for j := range cooc[i] {
+			assert(t, cooc[i][j], cooc[j][i], true)
+
+			// Pair of features can not be used together more
+			// than each single feature.
+			if cooc[i][j] > cooc[i][i] {
+				t.Fatalf("Expecting [%d][%d] <= [%d][%d], got"+
+					" %d > %d", i, j, i, i, cooc[i][j],
+					cooc[i][i])
+			}
+		}
+		nused += cooc[i][i]
+	}
+
+	// Class attribute is never used for splitting.
+	classIdx := samples.GetClassIndex()
+	assert(t, 0, cooc[classIdx][classIdx], true)
+
+	if nused <= 0 {
+		t.Fatal("Expecting at least one feature is used")
+	}
+}
+
+func TestPartialDependence(t *testing.T) {
+	forest, samples := newIrisForest(t, 50)
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// Partial dependence of "Iris-setosa" on petal length.
+	grid := []float64{1, 2, 3, 4, 5, 6, 7}
+
+	pd := forest.PartialDependence(2, grid, samples)
+
+	fmt.Println("[rf_test] partial dependence:", pd)
+
+	assert(t, len(grid), len(pd), true)
+
+	// Setosa have short petal, so the probability should decrease as
+	// petal length increase.
+	if pd[0] <= pd[len(pd)-1] {
+		t.Fatalf("Expecting decreasing partial dependence, got %v", pd)
+	}
+
+	for x := 1; x < len(pd); x++ {
+		if pd[x] > pd[x-1]+0.1 {
+			t.Fatalf("Expecting monotone partial dependence, got"+
+				" %v", pd)
+		}
+	}
+
+	assert(t, []float64(nil), forest.PartialDependence(4, grid, samples),
+		true)
+}
+
+func TestDumpTrees(t *testing.T) {
+	forest, samples := newIrisForest(t, 5)
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	dir, e := ioutil.TempDir("", "rf")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	e = forest.DumpTrees(dir)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	files, e := filepath.Glob(filepath.Join(dir, "tree_*.dot"))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, forest.NTree, len(files), true)
+
+	b, e := ioutil.ReadFile(filepath.Join(dir, "tree_0.dot"))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, forest.Trees()[0].ToDOT(), string(b), true)
+}
+
+func TestGrowMore(t *testing.T) {
+	newForest := func(ntree int) (*rf.Runtime, *tabula.Claset) {
+		forest, samples := newIrisForest(t, ntree)
+		forest.RunOOB = true
+		return forest, samples
+	}
+
+	forest, samples := newForest(100)
+
+	rand.Seed(1)
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	grown, samples := newForest(50)
+
+	e = grown.GrowMore(50, samples)
+	assert(t, rf.ErrNotBuilt, e, true)
+
+	rand.Seed(1)
+	e = grown.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	e = grown.GrowMore(50, samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert(t, forest.NTree, grown.NTree, true)
+	assert(t, len(forest.Trees()), len(grown.Trees()), true)
+	assert(t, forest.BagIndices(), grown.BagIndices(), true)
+
+	for x := range forest.Trees() {
+		assert(t, forest.Trees()[x].ToDOT(), grown.Trees()[x].ToDOT(),
+			true)
+	}
+
+	assert(t, forest.OOBErrorSteps(), grown.OOBErrorSteps(), true)
+}
+
+func TestReset(t *testing.T) {
+	forest, samples := newIrisForest(t, 5)
+	forest.RunOOB = true
+
+	for x := 0; x < 2; x++ {
+		forest.Reset()
+
+		e := forest.Build(samples)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert(t, forest.NTree, len(forest.Trees()), true)
+		assert(t, forest.NTree, len(forest.BagIndices()), true)
+		assert(t, forest.NTree, len(*forest.OOBStats()), true)
+		assert(t, int64(forest.NTree), forest.StatTotal().ID, true)
+	}
+}
+
+func TestBuildErrorCloseStatsFile(t *testing.T) {
+	const fdDir = "/proc/self/fd"
+
+	if _, e := os.Stat(fdDir); e != nil {
+		t.Skip("can not count open files:", e)
+	}
+
+	samples := readIris(t)
+
+	countFD := func() int {
+		fds, e := ioutil.ReadDir(fdDir)
+		if e != nil {
+			t.Fatal(e)
+		}
+		return len(fds)
+	}
+
+	before := countFD()
+
+	for x := 0; x < 10; x++ {
+		forest := rf.Runtime{
+			Runtime: classifier.Runtime{
+				OOBStatsFile: "iris.oob",
+			},
+		}
+
+		// The second bag is nil, so build will fail after the
+		// statistic file is opened.
+		bags := []tabula.ClasetInterface{samples, nil}
+
+		e := forest.BuildFromBags(bags)
+		assert(t, rf.ErrNoInput, e, true)
+	}
+
+	assert(t, before, countFD(), true)
+}
+
+func TestBuildDurations(t *testing.T) {
+	forest, samples := newIrisForest(t, 10)
+	forest.RunOOB = true
+
+	start := time.Now()
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	elapsed := time.Since(start)
+
+	durations := forest.BuildDurations()
+
+	fmt.Println("[rf_test] build durations:", durations)
+
+	assert(t, forest.NTree, len(durations), true)
+
+	var total time.Duration
+	for x, d := range durations {
+		if d <= 0 {
+			t.Fatalf("Expecting positive duration on tree %d, got %v",
+				x, d)
+		}
+		total += d
+	}
+
+	if total > elapsed {
+		t.Fatalf("Expecting total duration %v <= elapsed %v", total,
+			elapsed)
+	}
+}
+
+func TestLastOOBSet(t *testing.T) {
+	replacement := false
+	forest, samples := newIrisForest(t, 5)
+	forest.PercentBoot = 66
+	forest.Replacement = &replacement
+
+	assert(t, nil, forest.LastOOBSet(), true)
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	oobset := forest.LastOOBSet()
+	if oobset == nil {
+		t.Fatal("Expecting OOB set of the last tree")
+	}
+
+	// Without replacement, the OOB set contain all samples that is not
+	// in the bag, which is about (1 - PercentBoot/100) * nrow.
+	nrow := samples.GetNRow()
+	bags := forest.BagIndices()
+	exp := nrow - len(bags[len(bags)-1])
+
+	if exp < nrow*30/100 || exp > nrow*40/100 {
+		t.Fatalf("Expecting about 34%% OOB samples, got %d", exp)
+	}
+
+	fmt.Println("[rf_test] last OOB set:", oobset.GetNRow())
+
+	assert(t, exp, oobset.GetNRow(), true)
+
+	oobs := forest.OOBIndices()
+	assert(t, len(oobs[len(oobs)-1]), oobset.GetNRow(), true)
+}
+
+func TestPredictDataset(t *testing.T) {
+	forest, samples := newIrisForest(t, 10)
+
+	_, e := forest.PredictDataset(samples)
+	assert(t, rf.ErrNotBuilt, e, true)
+
+	e = forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	out, e := forest.PredictDataset(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	fmt.Println("[rf_test] predict dataset:", out.GetRow(0))
+
+	assert(t, samples.GetNColumn()+2, out.GetNColumn(), true)
+	assert(t, samples.GetNRow(), out.GetNRow(), true)
+
+	names := out.GetColumnsName()
+	assert(t, rf.ColumnPrediction, names[len(names)-2], true)
+	assert(t, rf.ColumnProbability, names[len(names)-1], true)
+
+	for x, row := range *out.GetRows() {
+		assert(t, samples.GetRow(x).Len()+2, row.Len(), true)
+	}
+}
+
+func TestClassifySetConcurrent(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/phoneme/phoneme.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	forest := rf.Runtime{
+		Runtime: classifier.Runtime{
+			OOBStatsFile: "phoneme.oob",
+		},
+		NTree: 20,
+	}
+
+	e = forest.Build(&samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	forest.NWorker = 1
+	expPredicts, expCM, expProbs := forest.ClassifySet(&samples, nil)
+
+	forest.NWorker = 4
+	gotPredicts, gotCM, gotProbs := forest.ClassifySet(&samples, nil)
+
+	assert(t, expPredicts, gotPredicts, true)
+	assert(t, expProbs, gotProbs, true)
+	assert(t, expCM.String(), gotCM.String(), true)
+}
+
+func TestSoftVote(t *testing.T) {
+	forest, samples := newIrisForest(t, 50)
+
+	rand.Seed(1)
+
+	bag, oob, _, _ := tabula.RandomPickRows(samples, 100, false)
+
+	train := bag.(tabula.ClasetInterface)
+	test := oob.(tabula.ClasetInterface)
+
+	train.SetClassIndex(samples.GetClassIndex())
+	test.SetClassIndex(samples.GetClassIndex())
+
+	e := forest.Build(train)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	actuals := test.GetClassAsStrings()
+
+	accuracy := func() float64 {
+		match := 0
+		for x, row := range *test.GetRows() {
+			if forest.Classify(row) == actuals[x] {
+				match++
+			}
+		}
+		return float64(match) / float64(len(actuals))
+	}
+
+	hard := accuracy()
+
+	forest.SoftVote = true
+
+	soft := accuracy()
+
+	fmt.Printf("[rf_test] accuracy, hard vote: %f, soft vote: %f\n",
+		hard, soft)
+
+	if soft < 0.85 {
+		t.Fatalf("Expecting soft vote accuracy >= 0.85, got %f", soft)
+	}
+	if soft < hard-0.05 {
+		t.Fatalf("Expecting soft vote accuracy %f close to or above"+
+			" hard vote %f", soft, hard)
+	}
+
+	_, probs := forest.Predict(test.GetRow(0))
+
+	sum := 0.0
+	for _, p := range probs {
+		sum += p
+	}
+
+	if math.Abs(sum-1) > 1e-9 {
+		t.Fatalf("Expecting soft vote probabilities sum to 1, got %f",
+			sum)
+	}
+}
+
+func TestConfusionMatrices(t *testing.T) {
+	forest, samples := newIrisForest(t, 10)
+	forest.RunOOB = true
+
+	assert(t, (*classifier.CM)(nil), forest.LastConfusionMatrix(), true)
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	cms := forest.ConfusionMatrices()
+
+	assert(t, forest.NTree, len(cms), true)
+	assert(t, &cms[len(cms)-1], forest.LastConfusionMatrix(), true)
+}
+
+func TestMeanLeafCount(t *testing.T) {
+	forest, samples := newIrisForest(t, 10)
+
+	assert(t, 0.0, forest.MeanLeafCount(), true)
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	total := 0
+	for _, tree := range forest.Trees() {
+		total += tree.LeafCount()
+	}
+
+	assert(t, float64(total)/float64(forest.NTree), forest.MeanLeafCount(),
+		true)
+}
+
+func TestAutoTuneMtry(t *testing.T) {
+	forest, samples := newIrisForest(t, 10)
+	forest.AutoTuneMtry = true
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	fmt.Println("[rf_test] tuned mtry:", forest.NRandomFeature)
+
+	// Iris have four features, so the candidates is 1, 2, and 4.
+	switch forest.NRandomFeature {
+	case 1, 2, 4:
+	default:
+		t.Fatalf("Expecting mtry in 1, 2, or 4, got %d",
+			forest.NRandomFeature)
+	}
+
+	assert(t, forest.NTree, len(forest.Trees()), true)
+}
+
+func TestString(t *testing.T) {
+	forest, samples := newIrisForest(t, 10)
+	forest.RunOOB = true
+	forest.NRandomFeature = 2
+	forest.PercentBoot = 50
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	s := forest.String()
+
+	fmt.Println("[rf_test] forest:", s)
+
+	exps := []string{
+		"NTree:10",
+		"NRandomFeature:2",
+		"PercentBoot:50",
+		"NTreeBuilt:10",
+		fmt.Sprintf("OOBErrorMean:%f", forest.StatTotal().OobErrorMean),
+	}
+
+	for _, exp := range exps {
+		if !strings.Contains(s, exp) {
+			t.Fatalf("Expecting %q in %q", exp, s)
+		}
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/phoneme/phoneme.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	trainset, rest := dataset.TrainTestSplit(&samples, 0.6, 1, true)
+	calibset, testset := dataset.TrainTestSplit(rest, 0.5, 2, true)
+
+	forest := rf.Runtime{
+		Runtime: classifier.Runtime{
+			OOBStatsFile: "phoneme.oob",
+		},
+		NTree: 20,
+	}
+
+	e = forest.Calibrate(rf.CalibrationPlatt, calibset)
+	assert(t, rf.ErrNotBuilt, e, true)
+
+	e = forest.Build(trainset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	e = forest.Calibrate("unknown", calibset)
+	assert(t, rf.ErrCalibrationMethod, e, true)
+
+	e = forest.Calibrate(rf.CalibrationPlatt, calibset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	vs := trainset.GetClassValueSpace()
+	actuals := testset.GetClassAsStrings()
+
+	var probs, calibProbs [][]float64
+	for _, row := range *testset.GetRows() {
+		_, p := forest.Predict(row)
+		probs = append(probs, p)
+
+		_, p = forest.PredictCalibrated(row)
+		calibProbs = append(calibProbs, p)
+	}
+
+	brier := classifier.BrierScore(actuals, probs, vs)
+	calibBrier := classifier.BrierScore(actuals, calibProbs, vs)
+
+	fmt.Printf("[rf_test] Brier score: %f, calibrated: %f\n", brier,
+		calibBrier)
+
+	if calibBrier >= brier {
+		t.Fatalf("Expecting Brier score after calibration %f less"+
+			" than %f", calibBrier, brier)
+	}
+
+	// Build again must clear the calibration of previous trees.
+	e = forest.Build(trainset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	row := testset.GetRow(0)
+	_, exp := forest.Predict(row)
+	_, got := forest.PredictCalibrated(row)
+
+	assert(t, exp, got, true)
+}
+
+func TestBootFraction(t *testing.T) {
+	phoneme := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/phoneme/phoneme.dsv", &phoneme)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// Create dataset with 1000 rows.
+	samples := phoneme.Clone().(tabula.ClasetInterface)
+	for x := 0; x < 1000; x++ {
+		samples.PushRow(phoneme.GetRow(x).Clone())
+	}
+
+	forest := rf.Runtime{
+		Runtime: classifier.Runtime{
+			OOBStatsFile: "phoneme.oob",
+		},
+		NTree:        2,
+		BootFraction: 0.632,
+	}
+
+	e = forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	for _, bagIdx := range forest.BagIndices() {
+		assert(t, 632, len(bagIdx), true)
+	}
+}
+
+func TestFeatureImportance(t *testing.T) {
+	forest, samples := newIrisForest(t, 50)
+
+	assert(t, true, forest.FeatureImportance() == nil, true)
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	imps := forest.FeatureImportance()
+
+	fmt.Println("[rf_test] feature importance:", imps)
+
+	sum := 0.0
+	for _, v := range imps {
+		sum += v
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Fatalf("Expecting sum of importance is 1, got %f", sum)
+	}
+
+	// Petal width (3) is more important than sepal width (1).
+	if imps[3] <= imps[1] {
+		t.Fatalf("Expecting petal width importance %f greater than"+
+			" sepal width %f", imps[3], imps[1])
+	}
+}
+
+func TestFeatureWeights(t *testing.T) {
+	forest, samples := newIrisForest(t, 50)
+
+	// Petal width, at index 3, have zero weight.
+	forest.FeatureWeights = []float64{1, 1, 1, 0, 0}
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	imps := forest.FeatureImportance()
+
+	fmt.Println("[rf_test] feature importance with weights:", imps)
+
+	assert(t, 0.0, imps[3], true)
+}
+
+func TestBagging(t *testing.T) {
+	var oobErrors []float64
+
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../../testdata/phoneme/phoneme.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	forest := rf.Runtime{
+		Runtime: classifier.Runtime{
+			OOBStatsFile: "phoneme.oob",
+		},
+		NTree: 20,
+	}
+
+	for _, bagging := range []bool{true, false} {
+		forest.Reset()
+		forest.Bagging = bagging
+
+		// Use the same seed, so both forest use the same bootstrap
+		// samples and only differ by their features.
+		rand.Seed(1)
+
+		e = forest.Build(&samples)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		mtry := forest.Trees()[0].NRandomFeature
+
+		if bagging {
+			assert(t, 0, forest.NRandomFeature, true)
+			assert(t, samples.GetNColumn()-1, mtry, true)
+		} else {
+			assert(t, 2, forest.NRandomFeature, true)
+			assert(t, 2, mtry, true)
+		}
+
+		oobError := forest.OOBConfusionMatrix().GetFalseRate()
+		if oobError <= 0 || oobError >= 1 {
+			t.Fatalf("Expecting OOB error between 0 and 1, got %f",
+				oobError)
+		}
+
+		oobErrors = append(oobErrors, oobError)
+	}
+
+	fmt.Printf("[rf_test] OOB error bagging: %f, random forest: %f\n",
+		oobErrors[0], oobErrors[1])
+
+	if oobErrors[0] == oobErrors[1] {
+		t.Fatalf("Expecting different OOB error between bagging and"+
+			" random forest, got %f", oobErrors[0])
+	}
+}
+
+func TestTuneMtryRange(t *testing.T) {
+	samples := readIris(t)
+
+	// Build the forests sequentially and with two workers.
+	for _, nworker := range []int{0, 2} {
+		best, oobByMtry := rf.TuneMtry(samples, 1, 4, 20, 66, nworker)
+
+		fmt.Println("[rf_test] nworker:", nworker, "best mtry:", best,
+			"OOB errors:", oobByMtry)
+
+		assert(t, 4, len(oobByMtry), true)
+
+		if best < 1 || best > 4 {
+			t.Fatalf("Expecting best mtry between 1 and 4, got %d",
+				best)
+		}
+		for x, oobErr := range oobByMtry {
+			if math.IsNaN(oobErr) || oobErr < 0 || oobErr > 1 {
+				t.Fatalf("Expecting OOB error of mtry %d between"+
+					" 0 and 1, got %f", x+1, oobErr)
+			}
+			if oobErr < oobByMtry[best-1] {
+				t.Fatalf("Expecting OOB error of best mtry %d is"+
+					" the minimum, got %v", best, oobByMtry)
+			}
+		}
+	}
+}
+
+func TestOOBErrorCI(t *testing.T) {
+	var widths []float64
+
+	for _, n := range []int{150, 30} {
+		forest, iris := newIrisForest(t, 20)
+
+		// Take every n-th rows, so all classes is included.
+		samples := iris.Clone().(tabula.ClasetInterface)
+		step := iris.GetNRow() / n
+		for x := 0; x < iris.GetNRow(); x += step {
+			samples.PushRow(iris.GetRow(x).Clone())
+		}
+
+		e := forest.Build(samples)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		oobErr := forest.OOBConfusionMatrix().GetFalseRate()
+		low, high := forest.OOBErrorCI(0.95)
+
+		fmt.Printf("[rf_test] n %d, OOB error %f in [%f, %f]\n", n,
+			oobErr, low, high)
+
+		if oobErr < low || oobErr > high {
+			t.Fatalf("Expecting OOB error %f in [%f, %f]", oobErr,
+				low, high)
+		}
+
+		widths = append(widths, high-low)
+	}
+
+	if widths[1] <= widths[0] {
+		t.Fatalf("Expecting interval widens on less samples, got %v",
+			widths)
+	}
+}
+
+func TestClusterByProximity(t *testing.T) {
+	forest, samples := newIrisForest(t, 50)
+
+	assert(t, true, forest.ClusterByProximity(3) == nil, true)
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	labels := forest.ClusterByProximity(3)
+
+	assert(t, samples.GetNRow(), len(labels), true)
+
+	// Count the species in each cluster, and sum the majority species.
+	classes := samples.GetClassAsStrings()
+	counts := make([]map[string]int, 3)
+	for x := range counts {
+		counts[x] = make(map[string]int)
+	}
+	for x, label := range labels {
+		if label < 0 || label >= 3 {
+			t.Fatalf("Expecting cluster label in [0, 3), got %d",
+				label)
+		}
+		counts[label][classes[x]]++
+	}
+
+	nmajor := 0
+	for _, count := range counts {
+		max := 0
+		for _, n := range count {
+			if n > max {
+				max = n
+			}
+		}
+		nmajor += max
+	}
+
+	purity := float64(nmajor) / float64(len(labels))
+
+	fmt.Println("[rf_test] cluster counts:", counts, "purity:", purity)
+
+	if purity < 0.8 {
+		t.Fatalf("Expecting cluster purity at least 0.8, got %f",
+			purity)
+	}
+}
+
+func TestOutlierScores(t *testing.T) {
+	forest, samples := newIrisForest(t, 50)
+
+	// Inject virginica sample labeled as setosa.
+	outlier := samples.GetRow(samples.GetNRow() - 1).Clone()
+	(*outlier)[samples.GetClassIndex()].SetString("Iris-setosa")
+	samples.PushRow(outlier)
+
+	outlierIdx := samples.GetNRow() - 1
+
+	assert(t, true, forest.OutlierScores() == nil, true)
+
+	e := forest.Build(samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	scores := forest.OutlierScores()
+
+	assert(t, samples.GetNRow(), len(scores), true)
+
+	fmt.Println("[rf_test] outlier score:", scores[outlierIdx])
+
+	if scores[outlierIdx] <= 10 {
+		t.Fatalf("Expecting outlier score greater than 10, got %f",
+			scores[outlierIdx])
+	}
+	classes := samples.GetClassAsStrings()
+	for x, score := range scores {
+		if classes[x] != "Iris-setosa" {
+			continue
+		}
+		if score > scores[outlierIdx] {
+			t.Fatalf("Expecting outlier has the highest score in"+
+				" its class, got sample %d with score %f", x,
+				score)
+		}
+	}
+}
+
+func TestSampleWeights(t *testing.T) {
+	forest, samples := newIrisForest(t, 20)
+
+	// Set zero weight on every fifth samples.
+	weights := make([]float64, samples.GetNRow())
+	for x := range weights {
+		if x%5 != 0 {
+			weights[x] = float64(1 + x%3)
+		}
+	}
+
+	forest.SampleWeights = weights[1:]
+
+	e := forest.Build(samples)
+	assert(t, rf.ErrInvalidSampleWeights, e, true)
+
+	// Only samples of the first class have positive weight.
+	classes := samples.GetClassAsStrings()
+	singleWeights := make([]float64, len(classes))
+	for x, class := range classes {
+		if class == classes[0] {
+			singleWeights[x] = 1
+		}
+	}
+
+	forest.Reset()
+	forest.SampleWeights = singleWeights
+
+	e = forest.Build(samples)
+	assert(t, rf.ErrInvalidSampleWeights, e, true)
+
+	for _, replacement := range []bool{true, false} {
+		forest.Reset()
+		forest.SampleWeights = weights
+		forest.Replacement = &replacement
+
+		e = forest.Build(samples)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		for x, bagIdx := range forest.BagIndices() {
+			for _, idx := range bagIdx {
+				if weights[idx] == 0 {
+					t.Fatalf("Expecting sample %d with zero"+
+						" weight not in bag %d", idx, x)
+				}
+			}
+		}
+
+		for x, oobIdx := range forest.OOBIndices() {
+			n := 0
+			for _, idx := range oobIdx {
+				if weights[idx] == 0 {
+					n++
+				}
+			}
+			if n != samples.GetNRow()/5 {
+				t.Fatalf("Expecting all samples with zero weight"+
+					" in OOB %d, got %d", x, n)
+			}
+		}
+	}
+}
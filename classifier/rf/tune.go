@@ -0,0 +1,245 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rf
+
+import (
+	"fmt"
+	"github.com/shuLhan/tabula"
+	"math"
+	"os"
+	"sync"
+)
+
+const (
+	// DefTuneNTree default number of tree in each forest that is build
+	// when tuning the number of random feature.
+	DefTuneNTree = 20
+
+	// DefTuneNSample default maximum number of samples used when tuning
+	// the number of random feature.
+	DefTuneNSample = 1000
+)
+
+//
+// mtryGrid return the candidates of number of random feature for dataset
+// with `nfeature` features: half, one, and two times the square-root of
+// number of features, bounded between one and number of features.
+//
+func mtryGrid(nfeature int) (grid []int) {
+	sqrt := math.Sqrt(float64(nfeature))
+
+	for _, f := range []float64{0.5, 1, 2} {
+		mtry := int(sqrt * f)
+		if mtry < 1 {
+			mtry = 1
+		}
+		if mtry > nfeature {
+			mtry = nfeature
+		}
+		if len(grid) > 0 && grid[len(grid)-1] == mtry {
+			continue
+		}
+		grid = append(grid, mtry)
+	}
+
+	return grid
+}
+
+/*
+autoTuneMtry will select the number of random feature, from the candidates of
+half, one, and two times the square-root of number of features, that
+minimize the OOB error of forest on `samples`.
+
+Algorithm,
+
+(1) If number of samples is greater than DefTuneNSample, select
+DefTuneNSample samples randomly.
+(2) For each candidate,
+(2.1) build the forest with DefTuneNTree trees using the candidate as
+number of random feature,
+(2.2) compute the OOB error of forest.
+(3) Return the candidate with minimum OOB error. If two or more candidates
+have the same OOB error, the smallest one is selected.
+*/
+func (forest *Runtime) autoTuneMtry(samples tabula.ClasetInterface) (
+	mtry int, e error,
+) {
+	if samples == nil || samples.GetNRow() <= 0 {
+		return 0, ErrNoInput
+	}
+
+	// (1)
+	subset := samples
+	if samples.GetNRow() > DefTuneNSample {
+		bag, _, _, _ := tabula.RandomPickRows(samples, DefTuneNSample,
+			false)
+
+		subset = bag.(tabula.ClasetInterface)
+		subset.SetClassIndex(samples.GetClassIndex())
+	}
+
+	minErr := math.Inf(1)
+
+	// (2)
+	for _, candidate := range mtryGrid(samples.GetNColumn() - 1) {
+		tuner := &Runtime{
+			NTree:          DefTuneNTree,
+			NRandomFeature: candidate,
+			PercentBoot:    forest.PercentBoot,
+			BootFraction:   forest.BootFraction,
+			Replacement:    forest.Replacement,
+		}
+
+		// (2.1) and (2.2)
+		oobErr, e := oobErrorOf(tuner, subset)
+		if e != nil {
+			return 0, e
+		}
+		if math.IsNaN(oobErr) {
+			continue
+		}
+
+		if DEBUG >= 1 {
+			fmt.Println(tag, "mtry:", candidate, "OOB error:", oobErr)
+		}
+
+		// (3)
+		if oobErr < minErr {
+			minErr = oobErr
+			mtry = candidate
+		}
+	}
+
+	return mtry, nil
+}
+
+/*
+TuneMtry will build forest with `ntree` trees and `percent` bootstrap for
+each number of random feature from `minFeat` to `maxFeat`, inclusive, and
+return the one with minimum OOB error, and the OOB error of each number of
+random feature, where oobByMtry[0] is the OOB error of `minFeat`.
+
+Each forest is build on their own copy of samples. At most `nworker` forests
+is build concurrently, so only `nworker` copies of samples is kept in memory
+at a time. If `nworker` is less or equal to one, the forests is build
+sequentially.
+The `minFeat` is bounded to one and `maxFeat` is bounded to number of
+features in samples. If forest can not be build, their OOB error will be NaN.
+If two or more number of random feature have the same OOB error, the
+smallest one is selected.
+*/
+func TuneMtry(samples tabula.ClasetInterface, minFeat, maxFeat, ntree,
+	percent, nworker int,
+) (
+	bestMtry int, oobByMtry []float64,
+) {
+	if samples == nil || samples.GetNRow() <= 0 {
+		return 0, nil
+	}
+
+	nfeature := samples.GetNColumn() - 1
+	if minFeat < 1 {
+		minFeat = 1
+	}
+	if maxFeat > nfeature {
+		maxFeat = nfeature
+	}
+	if maxFeat < minFeat {
+		return 0, nil
+	}
+
+	oobByMtry = make([]float64, maxFeat-minFeat+1)
+
+	tune := func(x int) {
+		tuner := &Runtime{
+			NTree:          ntree,
+			NRandomFeature: minFeat + x,
+			PercentBoot:    percent,
+		}
+
+		oobErr, e := oobErrorOf(tuner, copySamples(samples))
+		if e != nil {
+			oobErr = math.NaN()
+		}
+
+		oobByMtry[x] = oobErr
+	}
+
+	if nworker <= 1 {
+		for x := range oobByMtry {
+			tune(x)
+		}
+	} else {
+		var wg sync.WaitGroup
+
+		// sem limit the number of forests that is build concurrently.
+		sem := make(chan struct{}, nworker)
+
+		for x := range oobByMtry {
+			wg.Add(1)
+			sem <- struct{}{}
+
+			go func(x int) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				tune(x)
+			}(x)
+		}
+
+		wg.Wait()
+	}
+
+	minErr := math.Inf(1)
+	for x, oobErr := range oobByMtry {
+		if oobErr < minErr {
+			minErr = oobErr
+			bestMtry = minFeat + x
+		}
+	}
+
+	return bestMtry, oobByMtry
+}
+
+//
+// oobErrorOf will build the forest `tuner` using `samples` without writing
+// the OOB statistic, and return the OOB error of the forest.
+// It will return NaN if the OOB error can not be computed.
+//
+func oobErrorOf(tuner *Runtime, samples tabula.ClasetInterface) (
+	oobErr float64, e error,
+) {
+	tuner.OOBStatsFile = os.DevNull
+
+	e = tuner.Build(samples)
+	if e != nil {
+		return math.NaN(), e
+	}
+
+	cm := tuner.OOBConfusionMatrix()
+	if cm == nil {
+		return math.NaN(), nil
+	}
+
+	return cm.GetFalseRate(), nil
+}
+
+//
+// copySamples return a copy of `samples`, where each row is cloned.
+//
+func copySamples(samples tabula.ClasetInterface) (
+	copies tabula.ClasetInterface,
+) {
+	copies = samples.Clone().(tabula.ClasetInterface)
+	copies.SetClassIndex(samples.GetClassIndex())
+
+	for _, row := range *samples.GetRows() {
+		copies.PushRow(row.Clone())
+	}
+
+	copies.RecountMajorMinor()
+
+	return copies
+}
@@ -0,0 +1,141 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rf
+
+import (
+	"github.com/shuLhan/tabula"
+	"math/rand"
+	"sort"
+)
+
+//
+// checkSampleWeights will check that `weights` have the same length with
+// `classes`, none of it is negative, and at least one of it is positive.
+// If `classes` contain more than one class, the rows with positive weight
+// must contain at least two classes, otherwise all bootstrap samples will
+// contain only one class.
+// It will return the number of positive weights.
+//
+func checkSampleWeights(weights []float64, classes []string) (
+	npositive int, e error,
+) {
+	if len(weights) != len(classes) {
+		return 0, ErrInvalidSampleWeights
+	}
+
+	all := make(map[string]bool)
+	positives := make(map[string]bool)
+
+	for x, w := range weights {
+		if w < 0 {
+			return 0, ErrInvalidSampleWeights
+		}
+		all[classes[x]] = true
+		if w > 0 {
+			positives[classes[x]] = true
+			npositive++
+		}
+	}
+
+	if npositive <= 0 {
+		return 0, ErrInvalidSampleWeights
+	}
+	if len(all) > 1 && len(positives) < 2 {
+		return 0, ErrInvalidSampleWeights
+	}
+
+	return npositive, nil
+}
+
+/*
+weightedPickRows is the weighted version of tabula.RandomPickRows. It will
+select `n` rows from `samples` where each row is selected with probability
+proportional to its weight in `weights`, and return the selected rows,
+unselected rows (OOB), and their index.
+
+Algorithm,
+
+(1) Compute the cumulative sum of weights.
+(2) For each n,
+(2.1) pick random value between zero and total weights, and select the row
+where the cumulative sum is greater than the value;
+(2.2) if its without replacement, set the weight of selected row to zero and
+recompute the cumulative sum.
+(3) Rows that is not selected is the OOB rows.
+*/
+func weightedPickRows(samples tabula.ClasetInterface, n int,
+	weights []float64, isWithReplacement bool,
+) (
+	bag, oob tabula.ClasetInterface, bagIdx, oobIdx []int,
+) {
+	rows := samples.GetRows()
+	nrow := len(*rows)
+
+	// (1)
+	ws := make([]float64, nrow)
+	copy(ws, weights)
+
+	cumsum := make([]float64, nrow)
+	sum := cumulativeSum(ws, cumsum)
+
+	// (2)
+	picked := make([]bool, nrow)
+	bagIdx = make([]int, 0, n)
+
+	for ; n > 0 && sum > 0; n-- {
+		// (2.1)
+		v := rand.Float64() * sum
+
+		idx := sort.Search(nrow, func(i int) bool {
+			return cumsum[i] > v
+		})
+		// Value may rounded to the total weights, select the last
+		// row with positive weight.
+		for idx >= nrow || ws[idx] <= 0 {
+			idx--
+		}
+
+		bagIdx = append(bagIdx, idx)
+		picked[idx] = true
+
+		// (2.2)
+		if !isWithReplacement {
+			ws[idx] = 0
+			sum = cumulativeSum(ws, cumsum)
+		}
+	}
+
+	bag = samples.Clone().(tabula.ClasetInterface)
+	bag.SetClassIndex(samples.GetClassIndex())
+
+	for _, idx := range bagIdx {
+		bag.PushRow((*rows)[idx].Clone())
+	}
+
+	// (3)
+	oob = samples.Clone().(tabula.ClasetInterface)
+	oob.SetClassIndex(samples.GetClassIndex())
+
+	for idx, row := range *rows {
+		if !picked[idx] {
+			oob.PushRow(row.Clone())
+			oobIdx = append(oobIdx, idx)
+		}
+	}
+
+	return bag, oob, bagIdx, oobIdx
+}
+
+//
+// cumulativeSum will compute the cumulative sum of `weights` into `cumsum`,
+// and return the total weights.
+//
+func cumulativeSum(weights, cumsum []float64) (sum float64) {
+	for x, w := range weights {
+		sum += w
+		cumsum[x] = sum
+	}
+	return sum
+}
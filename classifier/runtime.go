@@ -58,6 +58,14 @@ type Runtime struct {
 	// perfs contain performance statistic per sample, after classifying
 	// sample on classifier.
 	perfs Stats
+
+	// prRecalls contain recall of each threshold in precision-recall
+	// curve.
+	prRecalls []float64
+
+	// prPrecisions contain precision of each threshold in
+	// precision-recall curve.
+	prPrecisions []float64
 }
 
 func init() {
@@ -96,6 +104,21 @@ func (rt *Runtime) Finalize() (e error) {
 	return rt.CloseOOBStatsFile()
 }
 
+//
+// Reset will close the statistic file if its open and clear all statistic,
+// so the runtime can be used again.
+//
+func (rt *Runtime) Reset() {
+	_ = rt.CloseOOBStatsFile()
+
+	rt.oobCms = nil
+	rt.oobStats = nil
+	rt.oobStatTotal = Stat{}
+	rt.perfs = nil
+	rt.prRecalls = nil
+	rt.prPrecisions = nil
+}
+
 //
 // OOBStats return all statistic objects.
 //
@@ -103,6 +126,25 @@ func (rt *Runtime) OOBStats() *Stats {
 	return &rt.oobStats
 }
 
+//
+// ConfusionMatrices return confusion matrix of OOB in each iteration (e.g.
+// each tree in forest). It will be empty if OOB is not computed.
+//
+func (rt *Runtime) ConfusionMatrices() []CM {
+	return rt.oobCms
+}
+
+//
+// LastConfusionMatrix return confusion matrix of OOB in the last iteration,
+// or nil if OOB is not computed.
+//
+func (rt *Runtime) LastConfusionMatrix() *CM {
+	if len(rt.oobCms) <= 0 {
+		return nil
+	}
+	return &rt.oobCms[len(rt.oobCms)-1]
+}
+
 //
 // StatTotal return total statistic.
 //
@@ -110,6 +152,23 @@ func (rt *Runtime) StatTotal() *Stat {
 	return &rt.oobStatTotal
 }
 
+//
+// OOBErrorSteps return the running mean of out-of-bag error after each
+// iteration (e.g. after each tree in forest), which can be used to plot the
+// learning curve of classifier.
+// The value will be zero if OOB is not computed.
+//
+func (rt *Runtime) OOBErrorSteps() (steps []float64) {
+	var sum float64
+
+	steps = make([]float64, len(rt.oobStats))
+	for x, stat := range rt.oobStats {
+		sum += stat.OobError
+		steps[x] = sum / float64(x+1)
+	}
+	return
+}
+
 //
 // AddOOBCM will append new confusion matrix.
 //
@@ -188,11 +247,14 @@ func (rt *Runtime) ComputeStatFromCM(stat *Stat, cm *CM) {
 		stat.Precision = float64(stat.TP) / t
 	}
 
-	t = (1 / stat.Precision) + (1 / stat.TPRate)
+	// F-measure = 2*TP / (2*TP + FP + FN), which is equal to the
+	// harmonic mean of precision and TP rate, but without dividing by
+	// zero when one of them is zero.
+	t = float64(2*stat.TP + stat.FP + stat.FN)
 	if t == 0 {
 		stat.FMeasure = 0
 	} else {
-		stat.FMeasure = 2 / t
+		stat.FMeasure = float64(2*stat.TP) / t
 	}
 
 	t = float64(stat.TP + stat.TN + stat.FP + stat.FN)
@@ -258,11 +320,11 @@ func (rt *Runtime) ComputeStatTotal(stat *Stat) {
 		t.Precision = float64(t.TP) / total
 	}
 
-	total = (1 / t.Precision) + (1 / t.TPRate)
+	total = float64(2*t.TP + t.FP + t.FN)
 	if total == 0 {
 		t.FMeasure = 0
 	} else {
-		t.FMeasure = 2 / total
+		t.FMeasure = float64(2*t.TP) / total
 	}
 
 	total = float64(t.TP + t.TN + t.FP + t.FN)
@@ -356,7 +418,7 @@ func (rt *Runtime) PrintStatTotal(st *Stat) {
 // Algorithm,
 // (1) Sort the probabilities in descending order.
 // (2) Sort the actuals and predicts using sorted index from probs
-// (3) Compute tpr, fpr, precision
+// (3) Compute tpr, fpr, precision, and precision-recall curve.
 // (4) Write performance to file.
 //
 func (rt *Runtime) Performance(samples tabula.ClasetInterface,
@@ -407,8 +469,13 @@ func (rt *Runtime) computePerfByProbs(samples tabula.ClasetInterface,
 
 	auc := float64(0)
 
+	rt.prRecalls = nil
+	rt.prPrecisions = nil
+
 	for x, p := range probs {
 		if p != pprev {
+			rt.addPRPoint(tp, fp, nactuals[0])
+
 			stat := Stat{}
 			stat.SetTPRate(tp, nactuals[0])
 			stat.SetFPRate(fp, nactuals[1])
@@ -431,6 +498,8 @@ func (rt *Runtime) computePerfByProbs(samples tabula.ClasetInterface,
 		}
 	}
 
+	rt.addPRPoint(tp, fp, nactuals[0])
+
 	stat := Stat{}
 	stat.SetTPRate(tp, nactuals[0])
 	stat.SetFPRate(fp, nactuals[1])
@@ -449,6 +518,73 @@ func (rt *Runtime) computePerfByProbs(samples tabula.ClasetInterface,
 	}
 }
 
+//
+// addPRPoint will add recall and precision of current threshold to
+// precision-recall curve. The point is not added if no sample is predicted as
+// positive, because the precision is undefined.
+//
+func (rt *Runtime) addPRPoint(tp, fp, npositive int64) {
+	if tp+fp == 0 || npositive == 0 {
+		return
+	}
+
+	rt.prRecalls = append(rt.prRecalls, float64(tp)/float64(npositive))
+	rt.prPrecisions = append(rt.prPrecisions, float64(tp)/float64(tp+fp))
+}
+
+//
+// PRCurve return the recall (x) and precision (y) of each probability
+// threshold, in descending order of threshold, from the last call of
+// Performance.
+//
+func (rt *Runtime) PRCurve() (recalls, precisions []float64) {
+	return rt.prRecalls, rt.prPrecisions
+}
+
+//
+// AUCPR compute the area under precision-recall curve using trapezoidal
+// rule, where `recalls` must be in ascending order. The curve start from
+// recall zero with the precision of the first point.
+//
+func AUCPR(recalls, precisions []float64) (auc float64) {
+	if len(recalls) <= 0 || len(recalls) != len(precisions) {
+		return 0
+	}
+
+	rprev := 0.0
+	pprev := precisions[0]
+
+	for x, r := range recalls {
+		auc += (r - rprev) * (precisions[x] + pprev) / 2
+
+		rprev = r
+		pprev = precisions[x]
+	}
+
+	return auc
+}
+
+//
+// AccuracyOf return the fraction of `predicts` that is equal to `actuals`.
+// This is lighter than computing confusion matrix when only accuracy is
+// needed. It will return zero if length of actuals and predicts is not
+// equal or empty.
+//
+func AccuracyOf(actuals, predicts []string) float64 {
+	if len(actuals) <= 0 || len(actuals) != len(predicts) {
+		return 0
+	}
+
+	match := 0
+	for x, actual := range actuals {
+		if predicts[x] == actual {
+			match++
+		}
+	}
+
+	return float64(match) / float64(len(actuals))
+}
+
 //
 // WritePerformance will write performance data to file.
 //
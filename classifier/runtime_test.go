@@ -0,0 +1,214 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package classifier_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/go-mining/classifier/cart"
+	"github.com/shuLhan/tabula"
+	"math"
+	"testing"
+)
+
+func TestPRCurve(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../testdata/phoneme/phoneme.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	vs := samples.GetClassValueSpace()
+	actuals := samples.GetClassAsStrings()
+
+	// Good classifier: positive samples get higher probability than
+	// negative samples, with one negative sample ranked in the middle.
+	predicts := make([]string, len(actuals))
+	probs := make([]float64, len(actuals))
+	for x, class := range actuals {
+		predicts[x] = class
+		if class == vs[0] {
+			probs[x] = 0.9
+		} else {
+			probs[x] = 0.1
+		}
+	}
+	for x, class := range actuals {
+		if class != vs[0] {
+			predicts[x] = vs[0]
+			probs[x] = 0.5
+			break
+		}
+	}
+
+	rt := classifier.Runtime{}
+	rt.Performance(&samples, predicts, probs)
+
+	recalls, precisions := rt.PRCurve()
+
+	fmt.Println("[classifier_test] PR recalls   :", recalls)
+	fmt.Println("[classifier_test] PR precisions:", precisions)
+
+	if len(precisions) <= 0 {
+		t.Fatal("Expecting precision-recall points")
+	}
+
+	assert(t, 1.0, precisions[0], true)
+
+	auc := classifier.AUCPR(recalls, precisions)
+
+	fmt.Println("[classifier_test] AUC-PR:", auc)
+
+	if math.Abs(auc-1) > 1e-9 {
+		t.Fatalf("Expecting AUC-PR 1, got %f", auc)
+	}
+}
+
+func TestComputeStatFromCMAllNegative(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../testdata/phoneme/phoneme.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	vs := samples.GetClassValueSpace()
+	actuals := samples.GetClassAsStrings()
+
+	// Classifier that predict all samples as negative.
+	predicts := make([]string, len(actuals))
+	for x := range predicts {
+		predicts[x] = vs[1]
+	}
+
+	cm := classifier.CM{}
+	cm.ComputeStrings(vs, actuals, predicts)
+
+	rt := classifier.Runtime{}
+	stat := classifier.Stat{}
+	rt.ComputeStatFromCM(&stat, &cm)
+
+	fmt.Println("[classifier_test] all negative stat:", stat)
+
+	assert(t, 0.0, stat.Precision, true)
+	assert(t, 0.0, stat.FMeasure, true)
+
+	rt.AddStat(&stat)
+	rt.ComputeStatTotal(&stat)
+	total := rt.StatTotal()
+
+	if math.IsNaN(total.FMeasure) {
+		t.Fatal("Expecting total F-measure is not NaN")
+	}
+	assert(t, 0.0, total.FMeasure, true)
+}
+
+func TestBestThreshold(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../testdata/phoneme/phoneme.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	vs := samples.GetClassValueSpace()
+	actuals := samples.GetClassAsStrings()
+
+	// Separable probabilities: all positive samples have probability
+	// greater than negative samples.
+	predicts := make([]string, len(actuals))
+	probs := make([]float64, len(actuals))
+	for x, class := range actuals {
+		predicts[x] = class
+		if class == vs[0] {
+			probs[x] = 0.6 + float64(x%3)*0.1
+		} else {
+			probs[x] = 0.1 + float64(x%3)*0.1
+		}
+	}
+
+	rt := classifier.Runtime{}
+	perfs := rt.Performance(&samples, predicts, probs)
+
+	threshold, j := classifier.BestThreshold(perfs, probs)
+
+	fmt.Println("[classifier_test] best threshold:", threshold, j)
+
+	assert(t, 1.0, j, true)
+	assert(t, 0.6, threshold, true)
+}
+
+func newCART() classifier.Classifier {
+	return &cart.Runtime{
+		SplitMethod: cart.SplitMethodGini,
+	}
+}
+
+func TestLeaveOneOut(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../testdata/iris/iris.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	mean, stats, e := classifier.LeaveOneOut(&samples, newCART)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	fmt.Println("[classifier_test] LOO mean:", mean)
+
+	assert(t, samples.GetNRow(), len(stats), true)
+
+	if mean.Accuracy < 0.8 {
+		t.Fatalf("Expecting LOO accuracy >= 0.8, got %v",
+			mean.Accuracy)
+	}
+}
+
+func TestMonteCarloCV(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../testdata/iris/iris.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	nsplit := 5
+
+	mean, stats, e := classifier.MonteCarloCV(&samples, newCART, nsplit,
+		0.3, 1)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	fmt.Println("[classifier_test] Monte-Carlo CV mean:", mean)
+
+	assert(t, nsplit, len(stats), true)
+
+	if mean.Accuracy < 0.8 {
+		t.Fatalf("Expecting Monte-Carlo CV accuracy >= 0.8, got %v",
+			mean.Accuracy)
+	}
+
+	// Each evaluation use 30% of samples for testing.
+	for _, stat := range stats {
+		n := stat.TP + stat.FP + stat.TN + stat.FN
+		if n > 45 {
+			t.Fatalf("Expecting at most 45 test samples, got %d",
+				n)
+		}
+	}
+
+	_, _, e = classifier.MonteCarloCV(&samples, newCART, nsplit, 1, 1)
+	assert(t, classifier.ErrInvalidTestFraction, e, true)
+}
+
+func TestAccuracyOf(t *testing.T) {
+	actuals := []string{"1", "1", "0", "0"}
+	predicts := []string{"1", "0", "0", "0"}
+
+	assert(t, 0.75, classifier.AccuracyOf(actuals, predicts), true)
+	assert(t, 0.0, classifier.AccuracyOf(actuals, predicts[:2]), true)
+	assert(t, 0.0, classifier.AccuracyOf(nil, nil), true)
+}
@@ -0,0 +1,114 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package classifier
+
+import (
+	"math"
+)
+
+const (
+	// logLossEpsilon define the minimum probability used in LogLoss, to
+	// avoid computing log of zero.
+	logLossEpsilon = 1e-15
+)
+
+//
+// LogLoss return the average negative log-likelihood (cross-entropy) of
+// probabilities `probs` on `actuals`.
+//
+// Each row in `probs` contain the probability of each class in `valueSpace`,
+// with the same order.
+// The probability is clipped into [1e-15, 1-1e-15] to avoid infinity.
+// If actual class is not in value space, its probability is zero.
+//
+// It will return zero if length of actuals and probs is not equal or empty.
+//
+func LogLoss(actuals []string, probs [][]float64, valueSpace []string) float64 {
+	if len(actuals) <= 0 || len(actuals) != len(probs) {
+		return 0
+	}
+
+	sum := 0.0
+	for x, actual := range actuals {
+		p := 0.0
+		for y, v := range valueSpace {
+			if v == actual && y < len(probs[x]) {
+				p = probs[x][y]
+				break
+			}
+		}
+
+		if p < logLossEpsilon {
+			p = logLossEpsilon
+		} else if p > 1-logLossEpsilon {
+			p = 1 - logLossEpsilon
+		}
+
+		sum -= math.Log(p)
+	}
+
+	return sum / float64(len(actuals))
+}
+
+//
+// BrierScore return the mean squared error between probabilities `probs` and
+// the one-hot encoding of `actuals`, averaged over all samples.
+//
+// Each row in `probs` contain the probability of each class in `valueSpace`,
+// with the same order.
+// A perfect classifier, which predict the actual class with probability one,
+// will have score zero.
+//
+// It will return zero if length of actuals and probs is not equal or empty.
+//
+func BrierScore(actuals []string, probs [][]float64, valueSpace []string) (
+	score float64,
+) {
+	if len(actuals) <= 0 || len(actuals) != len(probs) {
+		return 0
+	}
+
+	for x, actual := range actuals {
+		for y, v := range valueSpace {
+			p := 0.0
+			if y < len(probs[x]) {
+				p = probs[x][y]
+			}
+
+			exp := 0.0
+			if v == actual {
+				exp = 1
+			}
+
+			score += (p - exp) * (p - exp)
+		}
+	}
+
+	return score / float64(len(actuals))
+}
+
+//
+// BinomialCI return the Wilson score interval of proportion `p` (e.g. error
+// rate) from `n` samples, with the `confidence` level (e.g. 0.95).
+// It will return zero interval if `n` is less or equal to zero, or if
+// confidence is not between zero and one.
+//
+func BinomialCI(p float64, n int64, confidence float64) (low, high float64) {
+	if n <= 0 || confidence <= 0 || confidence >= 1 {
+		return 0, 0
+	}
+
+	z := math.Sqrt2 * math.Erfinv(confidence)
+	nf := float64(n)
+	z2 := z * z
+
+	center := (p + z2/(2*nf)) / (1 + z2/nf)
+	margin := z / (1 + z2/nf) * math.Sqrt(p*(1-p)/nf+z2/(4*nf*nf))
+
+	low = math.Max(0, center-margin)
+	high = math.Min(1, center+margin)
+
+	return low, high
+}
@@ -0,0 +1,92 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package classifier_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/go-mining/classifier"
+	"math"
+	"testing"
+)
+
+func TestLogLoss(t *testing.T) {
+	vs := []string{"1", "0"}
+	actuals := []string{"1", "0", "1"}
+	probs := [][]float64{
+		{0.8, 0.2},
+		{0.4, 0.6},
+		{0.5, 0.5},
+	}
+
+	exp := -(math.Log(0.8) + math.Log(0.6) + math.Log(0.5)) / 3
+	got := classifier.LogLoss(actuals, probs, vs)
+
+	if math.Abs(exp-got) > 1e-12 {
+		t.Fatalf("Expecting log-loss %f, got %f", exp, got)
+	}
+
+	// Zero probability on actual class must be clipped.
+	got = classifier.LogLoss([]string{"1"}, [][]float64{{0, 1}}, vs)
+
+	if math.IsInf(got, 0) || math.Abs(-math.Log(1e-15)-got) > 1e-9 {
+		t.Fatalf("Expecting clipped log-loss %f, got %f",
+			-math.Log(1e-15), got)
+	}
+
+	assert(t, 0.0, classifier.LogLoss(actuals, probs[:1], vs), true)
+}
+
+func TestBrierScore(t *testing.T) {
+	vs := []string{"1", "0"}
+	actuals := []string{"1", "0", "1"}
+
+	// Perfectly confident and correct classifier.
+	probs := [][]float64{
+		{1, 0},
+		{0, 1},
+		{1, 0},
+	}
+
+	assert(t, 0.0, classifier.BrierScore(actuals, probs, vs), true)
+
+	probs = [][]float64{
+		{0.8, 0.2},
+		{0.4, 0.6},
+		{0.5, 0.5},
+	}
+
+	exp := (0.04 + 0.04 + 0.16 + 0.16 + 0.25 + 0.25) / 3
+	got := classifier.BrierScore(actuals, probs, vs)
+
+	if math.Abs(exp-got) > 1e-12 {
+		t.Fatalf("Expecting Brier score %f, got %f", exp, got)
+	}
+
+	assert(t, 0.0, classifier.BrierScore(actuals, probs[:1], vs), true)
+}
+
+func TestBinomialCI(t *testing.T) {
+	p := 0.1
+
+	lowSmall, highSmall := classifier.BinomialCI(p, 50, 0.95)
+	lowLarge, highLarge := classifier.BinomialCI(p, 5000, 0.95)
+
+	fmt.Println("[classifier_test] CI n=50:", lowSmall, highSmall)
+	fmt.Println("[classifier_test] CI n=5000:", lowLarge, highLarge)
+
+	if lowLarge > p || highLarge < p {
+		t.Fatalf("Expecting %f in interval [%f, %f]", p, lowLarge,
+			highLarge)
+	}
+	if highSmall-lowSmall <= highLarge-lowLarge {
+		t.Fatalf("Expecting interval of small samples [%f, %f] wider"+
+			" than large samples [%f, %f]", lowSmall, highSmall,
+			lowLarge, highLarge)
+	}
+
+	low, high := classifier.BinomialCI(p, 0, 0.95)
+	assert(t, 0.0, low, true)
+	assert(t, 0.0, high, true)
+}
@@ -52,6 +52,12 @@ type Stat struct {
 	Accuracy float64
 	// AUC contain the area under curve.
 	AUC float64
+	// Duration contain actual time, in nanosecond resolution, between
+	// end and start time.
+	Duration time.Duration
+
+	// start contain the start time with nanosecond resolution.
+	start time.Time
 }
 
 // SetAUC will set the AUC value.
@@ -112,6 +118,8 @@ func (stat *Stat) Sum(other *Stat) {
 
 //
 // ToRow will convert the stat to tabula.row in the order of Stat field.
+// The time fields are in seconds, except the last field (Duration) which is
+// in nanoseconds.
 //
 func (stat *Stat) ToRow() (row *tabula.Row) {
 	row = &tabula.Row{}
@@ -133,6 +141,7 @@ func (stat *Stat) ToRow() (row *tabula.Row) {
 	row.PushBack(tabula.NewRecordReal(stat.FMeasure))
 	row.PushBack(tabula.NewRecordReal(stat.Accuracy))
 	row.PushBack(tabula.NewRecordReal(stat.AUC))
+	row.PushBack(tabula.NewRecordInt(int64(stat.Duration)))
 
 	return
 }
@@ -141,15 +150,19 @@ func (stat *Stat) ToRow() (row *tabula.Row) {
 // Start will start the timer.
 //
 func (stat *Stat) Start() {
-	stat.StartTime = time.Now().Unix()
+	stat.start = time.Now()
+	stat.StartTime = stat.start.Unix()
 }
 
 //
 // End will stop the timer and compute the elapsed time.
 //
 func (stat *Stat) End() {
-	stat.EndTime = time.Now().Unix()
+	end := time.Now()
+
+	stat.EndTime = end.Unix()
 	stat.ElapsedTime = stat.EndTime - stat.StartTime
+	stat.Duration = end.Sub(stat.start)
 }
 
 //
@@ -6,6 +6,7 @@ package classifier
 
 import (
 	"github.com/shuLhan/dsv"
+	"time"
 )
 
 /*
@@ -118,6 +119,16 @@ func (stats *Stats) Accuracies() (accuracies []float64) {
 	return
 }
 
+//
+// Durations return all durations.
+//
+func (stats *Stats) Durations() (durations []time.Duration) {
+	for _, stat := range *stats {
+		durations = append(durations, stat.Duration)
+	}
+	return
+}
+
 //
 // Write will write all statistic data to `file`.
 //
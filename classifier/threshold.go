@@ -0,0 +1,64 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package classifier
+
+import (
+	"sort"
+)
+
+/*
+BestThreshold return the probability threshold that maximize Youden's J
+statistic,
+
+	J = TPRate - FPRate
+
+using performance points `perfs` and probabilities `probs` from one call of
+Performance. Sample with probability greater or equal to the threshold
+should be classified as positive class.
+
+The first point in performance is a copy of the second point (see
+computePerfByProbs), so it is not scanned.
+If there is no performance point, it will return zero threshold and zero J.
+
+Algorithm,
+
+(1) Sort the unique probabilities in descending order, where the n-th
+performance point (n > 0) is the result of classifying samples with
+probability greater or equal to the (n-1)-th unique probability as positive.
+(2) Scan all points and return the first threshold with maximum J.
+*/
+func BestThreshold(perfs Stats, probs []float64) (threshold float64,
+	j float64,
+) {
+	// (1)
+	sorted := make([]float64, len(probs))
+	copy(sorted, probs)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+
+	uniq := make([]float64, 0, len(sorted))
+	for x, p := range sorted {
+		if x == 0 || p != sorted[x-1] {
+			uniq = append(uniq, p)
+		}
+	}
+
+	// (2)
+	found := false
+	for x := 1; x < len(perfs); x++ {
+		if x-1 >= len(uniq) {
+			break
+		}
+
+		v := perfs[x].TPRate - perfs[x].FPRate
+
+		if !found || v > j {
+			threshold = uniq[x-1]
+			j = v
+			found = true
+		}
+	}
+
+	return threshold, j
+}
@@ -8,8 +8,9 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/classifier"
 	"github.com/shuLhan/go-mining/classifier/cart"
+	"github.com/shuLhan/go-mining/dataset"
 	"github.com/shuLhan/tabula"
 	"io/ioutil"
 	"os"
@@ -21,11 +22,14 @@ var (
 	// DEBUG level, can be set from environment variable.
 	DEBUG          = 0
 	nRandomFeature = 0
+	// predictCfg point to the configuration file of samples that will be
+	// classified after the tree has been build.
+	predictCfg = ""
 )
 
 var usage = func() {
 	cmd := os.Args[0]
-	fmt.Fprintf(os.Stderr, "Usage of %s: [-n number] [config.dsv]\n", cmd)
+	fmt.Fprintf(os.Stderr, "Usage of %s: [-n number] [-predict input.dsv] [config.dsv]\n", cmd)
 	flag.PrintDefaults()
 }
 
@@ -39,9 +43,12 @@ func init() {
 
 	flagUsage := []string{
 		"Number of random feature (default 0)",
+		"Classify samples in input file using the tree, and print" +
+			" the predictions and confusion matrix",
 	}
 
 	flag.IntVar(&nRandomFeature, "n", 0, flagUsage[0])
+	flag.StringVar(&predictCfg, "predict", "", flagUsage[1])
 }
 
 func trace(s string) (string, time.Time) {
@@ -75,6 +82,37 @@ func createCart(fcfg string) (*cart.Runtime, error) {
 	return cartrt, nil
 }
 
+//
+// predict will classify samples from file `fcfg` using tree in `cartrt`, and
+// print the prediction of each sample and the confusion matrix to standard
+// output.
+//
+func predict(cartrt *cart.Runtime, fcfg string) error {
+	testset := tabula.Claset{}
+	e := dataset.SimpleRead(fcfg, &testset)
+	if e != nil {
+		return e
+	}
+
+	vs := testset.GetClassValueSpace()
+	actuals := testset.GetClassAsStrings()
+	predicts := make([]string, 0, len(actuals))
+
+	for x, row := range *testset.GetRows() {
+		class := cartrt.Classify(row)
+		predicts = append(predicts, class)
+
+		fmt.Printf("%d,%s,%s\n", x, actuals[x], class)
+	}
+
+	cm := classifier.CM{}
+	cm.ComputeStrings(vs, actuals, predicts)
+
+	fmt.Println("[cart] CM:", &cm)
+
+	return nil
+}
+
 func main() {
 	defer un(trace("cart"))
 
@@ -94,17 +132,17 @@ func main() {
 	}
 
 	// Get dataset
-	dataset := tabula.Claset{}
-	_, e = dsv.SimpleRead(fcfg, &dataset)
+	trainset := tabula.Claset{}
+	e = dataset.SimpleRead(fcfg, &trainset)
 	if e != nil {
 		panic(e)
 	}
 
 	if DEBUG >= 1 {
-		fmt.Printf("[cart] Class index: %v\n", dataset.GetClassIndex())
+		fmt.Printf("[cart] Class index: %v\n", trainset.GetClassIndex())
 	}
 
-	e = cartrt.Build(&dataset)
+	e = cartrt.Build(&trainset)
 	if e != nil {
 		panic(e)
 	}
@@ -112,4 +150,11 @@ func main() {
 	if DEBUG >= 1 {
 		fmt.Println("[cart] CART tree:\n", cartrt)
 	}
+
+	if predictCfg != "" {
+		e = predict(cartrt, predictCfg)
+		if e != nil {
+			panic(e)
+		}
+	}
 }
@@ -8,8 +8,8 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"github.com/shuLhan/dsv"
 	"github.com/shuLhan/go-mining/classifier/crf"
+	"github.com/shuLhan/go-mining/dataset"
 	"github.com/shuLhan/tabula"
 	"io/ioutil"
 	"os"
@@ -144,7 +144,7 @@ func train() {
 
 	trainset := tabula.Claset{}
 
-	_, e = dsv.SimpleRead(trainCfg, &trainset)
+	e = dataset.SimpleRead(trainCfg, &trainset)
 	if e != nil {
 		panic(e)
 	}
@@ -157,7 +157,7 @@ func train() {
 
 func test() {
 	testset := tabula.Claset{}
-	_, e := dsv.SimpleRead(testCfg, &testset)
+	e := dataset.SimpleRead(testCfg, &testset)
 	if e != nil {
 		panic(e)
 	}
@@ -8,8 +8,9 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/classifier"
 	"github.com/shuLhan/go-mining/classifier/rf"
+	"github.com/shuLhan/go-mining/dataset"
 	"github.com/shuLhan/tabula"
 	"io/ioutil"
 	"os"
@@ -39,6 +40,9 @@ var (
 	trainCfg = ""
 	// testCfg point to the configuration file for testing
 	testCfg = ""
+	// jsonOutput if its true, the statistic of forest will be printed in
+	// JSON format to standard output after training.
+	jsonOutput = false
 
 	// forest the main object.
 	forest rf.Runtime
@@ -63,6 +67,7 @@ func init() {
 		"Performance file, where statistic of classifying data set will be written",
 		"Training configuration",
 		"Test configuration",
+		"Print total and per tree statistic in JSON to standard output",
 	}
 
 	flag.IntVar(&nTree, "ntree", -1, flagUsage[0])
@@ -74,6 +79,8 @@ func init() {
 
 	flag.StringVar(&trainCfg, "train", "", flagUsage[5])
 	flag.StringVar(&testCfg, "test", "", flagUsage[6])
+
+	flag.BoolVar(&jsonOutput, "json", false, flagUsage[7])
 }
 
 func trace() (start time.Time) {
@@ -124,9 +131,21 @@ func createRandomForest() error {
 		forest.PerfFile = perfFile
 	}
 
+	if !jsonOutput {
+		forest.OnTreeBuilt = printProgress
+	}
+
 	return nil
 }
 
+//
+// printProgress will print the number of tree that has been build.
+//
+func printProgress(treeIdx int, stat *classifier.Stat) {
+	fmt.Printf("%s tree %d/%d, elapsed %d s\n", tag, treeIdx+1,
+		forest.NTree, stat.ElapsedTime)
+}
+
 func train() {
 	e := createRandomForest()
 	if e != nil {
@@ -135,7 +154,7 @@ func train() {
 
 	trainset := tabula.Claset{}
 
-	_, e = dsv.SimpleRead(trainCfg, &trainset)
+	e = dataset.SimpleRead(trainCfg, &trainset)
 	if e != nil {
 		panic(e)
 	}
@@ -146,9 +165,30 @@ func train() {
 	}
 }
 
+//
+// printJSON will print the total statistic and statistic of each tree in
+// forest in JSON format.
+//
+func printJSON() {
+	out := struct {
+		StatTotal *classifier.Stat
+		Stats     *classifier.Stats
+	}{
+		StatTotal: forest.StatTotal(),
+		Stats:     forest.OOBStats(),
+	}
+
+	b, e := json.MarshalIndent(&out, "", "\t")
+	if e != nil {
+		panic(e)
+	}
+
+	fmt.Println(string(b))
+}
+
 func test() {
 	testset := tabula.Claset{}
-	_, e := dsv.SimpleRead(testCfg, &testset)
+	e := dataset.SimpleRead(testCfg, &testset)
 	if e != nil {
 		panic(e)
 	}
@@ -168,6 +208,7 @@ func test() {
 // (1) If trainCfg parameter is set,
 // (1.1) train the model,
 // (1.2) TODO: load saved model.
+// (1.3) print statistic in JSON if its requested.
 // (2) If testCfg parameter is set,
 // (2.1) Test the model using data from testCfg.
 //
@@ -181,6 +222,11 @@ func main() {
 	if trainCfg != "" {
 		// (1.1)
 		train()
+
+		// (1.3)
+		if jsonOutput {
+			printJSON()
+		}
 	} else {
 		// (1.2)
 		if len(flag.Args()) <= 0 {
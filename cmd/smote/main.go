@@ -118,7 +118,7 @@ func runSmote(smote *smote.Runtime, dataset *tabula.Claset) (e error) {
 		fmt.Println("[smote] # minority samples:", minorset.Len())
 	}
 
-	e = smote.Resampling(*minorset)
+	e = smote.ResamplingRows(*minorset)
 	if e != nil {
 		return
 	}
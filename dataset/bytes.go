@@ -0,0 +1,35 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"bytes"
+	"github.com/shuLhan/tabula"
+	"io"
+)
+
+//
+// ReadFromBytes will read dataset from dsv configuration `config` and the
+// content of input `data`, and return it as claset.
+// The "Input" and "Rejected" in configuration is ignored, the data is parsed
+// from memory using the input metadata in configuration, without writing it
+// to file system.
+// Line that can not be parsed is skipped.
+//
+func ReadFromBytes(config, data []byte) (ds tabula.ClasetInterface, e error) {
+	lr, e := newLineReader(config, bytes.NewReader(data))
+	if e != nil {
+		return nil, e
+	}
+
+	claset := &tabula.Claset{}
+
+	_, e = lr.read(claset, lr.MaxRows)
+	if e != nil && e != io.EOF {
+		return nil, e
+	}
+
+	return claset, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset_test
+
+import (
+	"github.com/shuLhan/go-mining/classifier/cart"
+	"github.com/shuLhan/go-mining/dataset"
+	"testing"
+)
+
+var bytesConfig = []byte(`{
+	"Input"			:"unused.dat"
+,	"MaxRows"		:-1
+,	"ClassIndex"		:2
+,	"DatasetMode"		:"matrix"
+,	"InputMetadata"		:
+	[{
+		"Name"			:"x"
+	,	"Separator"		:","
+	,	"Type"			:"real"
+	},{
+		"Name"			:"y"
+	,	"Separator"		:","
+	,	"Type"			:"real"
+	},{
+		"Name"			:"class"
+	,	"Type"			:"string"
+	,	"ValueSpace"		:
+		[
+			"low"
+		,	"high"
+		]
+	}]
+}`)
+
+var bytesData = []byte(`1.0,1.5,low
+1.2,1.1,low
+0.8,1.3,low
+1.1,0.9,low
+5.0,5.5,high
+5.2,4.8,high
+4.9,5.1,high
+5.3,5.2,high
+`)
+
+func TestReadFromBytes(t *testing.T) {
+	ds, e := dataset.ReadFromBytes(bytesConfig, bytesData)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if ds.GetNRow() != 8 {
+		t.Fatalf("Expecting 8 rows, got %d", ds.GetNRow())
+	}
+	if ds.GetClassIndex() != 2 {
+		t.Fatalf("Expecting class index 2, got %d",
+			ds.GetClassIndex())
+	}
+
+	rows := *ds.GetRows()
+	first := rows[0].Clone()
+	last := rows[len(rows)-1].Clone()
+
+	CART := &cart.Runtime{
+		SplitMethod: cart.SplitMethodGini,
+	}
+
+	e = CART.Build(ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if got := CART.Classify(first); got != "low" {
+		t.Fatalf("Expecting class low, got %s", got)
+	}
+	if got := CART.Classify(last); got != "high" {
+		t.Fatalf("Expecting class high, got %s", got)
+	}
+
+	_, e = dataset.ReadFromBytes([]byte("{"), bytesData)
+	if e == nil {
+		t.Fatal("Expecting error on invalid configuration")
+	}
+
+	_, e = dataset.ReadFromBytes([]byte("{}"), bytesData)
+	if e != dataset.ErrNoInputMetadata {
+		t.Fatalf("Expecting error %v, got %v",
+			dataset.ErrNoInputMetadata, e)
+	}
+
+	// Line that can not be parsed is skipped.
+	data := append([]byte("a,b,low\n"), bytesData...)
+
+	ds, e = dataset.ReadFromBytes(bytesConfig, data)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if ds.GetNRow() != 8 {
+		t.Fatalf("Expecting 8 rows, got %d", ds.GetNRow())
+	}
+}
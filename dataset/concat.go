@@ -0,0 +1,77 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"errors"
+	"github.com/shuLhan/tabula"
+	"github.com/shuLhan/tekstus"
+)
+
+var (
+	// ErrSchemaMismatch will tell you when two datasets does not have
+	// the same number of columns, column types, or class index.
+	ErrSchemaMismatch = errors.New("dataset: schema of datasets is not" +
+		" match")
+)
+
+//
+// Concat return new dataset that contain rows in `a` followed by rows in
+// `b`, with the same metadata and class index as `a`.
+// Both datasets must have the same number of columns, type of each column,
+// and class index, otherwise it will return ErrSchemaMismatch.
+// Each row is cloned, so modifying the returned dataset will not change `a`
+// or `b`.
+// The class value space is the class value space of `a` followed by class in
+// `b` that is not in `a`, and the majority and minority class is counted
+// from all rows.
+//
+func Concat(a, b tabula.ClasetInterface) (tabula.ClasetInterface, error) {
+	if a.GetNColumn() != b.GetNColumn() ||
+		a.GetClassIndex() != b.GetClassIndex() {
+		return nil, ErrSchemaMismatch
+	}
+
+	for x := 0; x < a.GetNColumn(); x++ {
+		if a.GetColumn(x).GetType() != b.GetColumn(x).GetType() {
+			return nil, ErrSchemaMismatch
+		}
+	}
+
+	ds := newSplitSet(a)
+
+	for _, row := range *a.GetRows() {
+		ds.PushRow(row.Clone())
+	}
+	for _, row := range *b.GetRows() {
+		ds.PushRow(row.Clone())
+	}
+
+	classCol := ds.GetClassColumn()
+	if classCol != nil {
+		classCol.ValueSpace = mergeValueSpace(a.GetClassValueSpace(),
+			b.GetClassValueSpace())
+	}
+
+	ds.RecountMajorMinor()
+
+	return ds, nil
+}
+
+//
+// mergeValueSpace return values in `a` followed by values in `b` that is not
+// in `a`.
+//
+func mergeValueSpace(a, b []string) (vs []string) {
+	vs = append(vs, a...)
+
+	for _, v := range b {
+		if !tekstus.StringsIsContain(vs, v) {
+			vs = append(vs, v)
+		}
+	}
+
+	return vs
+}
@@ -0,0 +1,110 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset_test
+
+import (
+	"bytes"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/dataset"
+	"github.com/shuLhan/tabula"
+	"reflect"
+	"testing"
+)
+
+func TestConcat(t *testing.T) {
+	iris := readIris(t)
+
+	ds, e := dataset.Concat(iris, iris)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if ds.GetNRow() != 300 {
+		t.Fatalf("Expecting 300 rows, got %d", ds.GetNRow())
+	}
+	if ds.GetClassIndex() != iris.GetClassIndex() {
+		t.Fatalf("Expecting class index %d, got %d",
+			iris.GetClassIndex(), ds.GetClassIndex())
+	}
+
+	first := rowsAsStrings(iris)
+	got := rowsAsStrings(ds)
+	for x, row := range got {
+		if row != first[x%len(first)] {
+			t.Fatalf("Expecting row %d is %s, got %s", x,
+				first[x%len(first)], row)
+		}
+	}
+
+	if iris.GetNRow() != 150 {
+		t.Fatalf("Expecting original dataset is not changed, got %d"+
+			" rows", iris.GetNRow())
+	}
+}
+
+func TestConcatValueSpace(t *testing.T) {
+	a, e := dataset.ReadFromBytes(bytesConfig, []byte(
+		"1.0,1.5,low\n1.2,1.1,low\n5.0,5.5,high\n"))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// Class "mid" is only known in `b`.
+	midConfig := bytes.Replace(bytesConfig, []byte(`"low"`),
+		[]byte(`"mid"`), 1)
+
+	b, e := dataset.ReadFromBytes(midConfig, []byte(
+		"5.2,4.8,high\n4.9,5.1,high\n3.0,3.0,mid\n"))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	ds, e := dataset.Concat(a, b)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := []string{"low", "high", "mid"}
+	if !reflect.DeepEqual(exp, ds.GetClassValueSpace()) {
+		t.Fatalf("Expecting class value space %v, got %v", exp,
+			ds.GetClassValueSpace())
+	}
+
+	if ds.MajorityClass() != "high" {
+		t.Fatalf("Expecting majority class high, got %s",
+			ds.MajorityClass())
+	}
+	if ds.MinorityClass() != "mid" {
+		t.Fatalf("Expecting minority class mid, got %s",
+			ds.MinorityClass())
+	}
+}
+
+func TestConcatMismatch(t *testing.T) {
+	iris := readIris(t)
+
+	// Different number of columns.
+	phoneme := &tabula.Claset{}
+	_, e := dsv.SimpleRead("../testdata/phoneme/phoneme.dsv", phoneme)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	_, e = dataset.Concat(iris, phoneme)
+	if e != dataset.ErrSchemaMismatch {
+		t.Fatalf("Expecting error %v, got %v",
+			dataset.ErrSchemaMismatch, e)
+	}
+
+	// Different class index.
+	other := iris.Clone().(tabula.ClasetInterface)
+	other.SetClassIndex(0)
+
+	_, e = dataset.Concat(iris, other)
+	if e != dataset.ErrSchemaMismatch {
+		t.Fatalf("Expecting error %v, got %v",
+			dataset.ErrSchemaMismatch, e)
+	}
+}
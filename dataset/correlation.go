@@ -0,0 +1,86 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"github.com/shuLhan/tabula"
+	"math"
+)
+
+//
+// CorrelationMatrix will compute the Pearson correlation between each
+// continuous column in dataset `ds`, excluding the class column and
+// non-continuous column.
+//
+// The returned matrix is symmetric, where the row and column is ordered by
+// index of continuous columns in dataset, and the diagonal is one.
+// If one of column have zero variance, their correlation with other column
+// is zero.
+//
+func CorrelationMatrix(ds tabula.ClasetInterface) (corr [][]float64) {
+	classIdx := ds.GetClassIndex()
+	cols := ds.GetColumns()
+
+	var data [][]float64
+	for x := range *cols {
+		col := &(*cols)[x]
+
+		if x == classIdx || col.GetType() != tabula.TReal {
+			continue
+		}
+
+		data = append(data, col.ToFloatSlice())
+	}
+
+	n := len(data)
+	corr = make([][]float64, n)
+	for x := range corr {
+		corr[x] = make([]float64, n)
+		corr[x][x] = 1
+	}
+
+	for x := 0; x < n; x++ {
+		for y := x + 1; y < n; y++ {
+			r := pearson(data[x], data[y])
+			corr[x][y] = r
+			corr[y][x] = r
+		}
+	}
+
+	return corr
+}
+
+//
+// pearson return the Pearson correlation coefficient between `a` and `b`.
+// It will return zero if one of them have zero variance.
+//
+func pearson(a, b []float64) float64 {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	if n == 0 {
+		return 0
+	}
+
+	meanA, _ := meanStd(a[:n])
+	meanB, _ := meanStd(b[:n])
+
+	var cov, varA, varB float64
+	for x := 0; x < n; x++ {
+		da := a[x] - meanA
+		db := b[x] - meanB
+
+		cov += da * db
+		varA += da * da
+		varB += db * db
+	}
+
+	if varA == 0 || varB == 0 {
+		return 0
+	}
+
+	return cov / math.Sqrt(varA*varB)
+}
@@ -0,0 +1,42 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/go-mining/dataset"
+	"testing"
+)
+
+func TestCorrelationMatrix(t *testing.T) {
+	ds := readIris(t)
+
+	corr := dataset.CorrelationMatrix(ds)
+
+	fmt.Println("[dataset_test] correlation:", corr)
+
+	if len(corr) != 4 {
+		t.Fatalf("Expecting 4x4 matrix, got %d rows", len(corr))
+	}
+
+	for x := range corr {
+		if corr[x][x] != 1 {
+			t.Fatalf("Expecting 1 on diagonal %d, got %f", x,
+				corr[x][x])
+		}
+		for y := range corr[x] {
+			if corr[x][y] != corr[y][x] {
+				t.Fatalf("Expecting symmetric matrix on %d,%d",
+					x, y)
+			}
+		}
+	}
+
+	// Petal length and petal width is highly correlated (0.963).
+	if corr[2][3] < 0.95 {
+		t.Fatalf("Expecting petal length and width correlation"+
+			" greater than 0.95, got %f", corr[2][3])
+	}
+}
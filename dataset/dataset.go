@@ -0,0 +1,12 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//
+// Package dataset provide helpers to inspect and transform dataset before it
+// is used by classifier or resampling module.
+//
+// Most of the function in this package work on columns of dataset, so the
+// dataset must be in columns or matrix mode.
+//
+package dataset
@@ -0,0 +1,178 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"github.com/shuLhan/tabula"
+)
+
+//
+// columnValueSpace return the value space of column `col`. If its not
+// defined, the value space is the distinct values in column, in the order of
+// their first occurrence.
+//
+func columnValueSpace(col *tabula.Column) (vs []string) {
+	if len(col.ValueSpace) > 0 {
+		return col.ValueSpace
+	}
+
+	exist := make(map[string]bool)
+	for _, v := range col.ToStringSlice() {
+		if !exist[v] {
+			exist[v] = true
+			vs = append(vs, v)
+		}
+	}
+
+	return vs
+}
+
+//
+// isNominal return true if column at index `x` in dataset `ds` is nominal
+// (string) column and its not the class.
+//
+func isNominal(ds tabula.ClasetInterface, x int) bool {
+	return x != ds.GetClassIndex() &&
+		ds.GetColumn(x).GetType() == tabula.TString
+}
+
+/*
+OneHotEncode will create and return new dataset where each nominal (string)
+column in dataset `ds`, except the class, is expanded into binary indicator
+columns, one for each value in their value space, with name "column=value".
+The indicator column have real type with value one if the row have that
+value, or zero otherwise. Other columns and class is copied as is.
+
+Algorithm,
+
+(1) Create the new columns type and name, and the value space of each
+nominal column.
+(2) Create new dataset and set the class index.
+(3) For each row in dataset, create new row by expanding the nominal value
+into indicator values.
+*/
+func OneHotEncode(ds tabula.ClasetInterface) tabula.ClasetInterface {
+	ncol := ds.GetNColumn()
+	classIdx := ds.GetClassIndex()
+
+	// (1)
+	var types []int
+	var names []string
+	var newClassIdx int
+
+	vss := make([][]string, ncol)
+
+	for x := 0; x < ncol; x++ {
+		col := ds.GetColumn(x)
+
+		if !isNominal(ds, x) {
+			if x == classIdx {
+				newClassIdx = len(types)
+			}
+			types = append(types, col.GetType())
+			names = append(names, col.GetName())
+			continue
+		}
+
+		vss[x] = columnValueSpace(col)
+		for _, v := range vss[x] {
+			types = append(types, tabula.TReal)
+			names = append(names, col.GetName()+"="+v)
+		}
+	}
+
+	// (2)
+	encoded := tabula.NewClaset(tabula.DatasetModeMatrix, types, names)
+
+	if classIdx >= 0 && classIdx < ncol {
+		cols := encoded.GetColumns()
+		(*cols)[newClassIdx].ValueSpace = ds.GetColumn(classIdx).ValueSpace
+
+		encoded.SetClassIndex(newClassIdx)
+	}
+
+	// (3)
+	for _, row := range *ds.GetRows() {
+		newRow := make(tabula.Row, 0, len(types))
+
+		for x, rec := range *row {
+			if !isNominal(ds, x) {
+				newRow = append(newRow, rec.Clone())
+				continue
+			}
+
+			v := rec.String()
+			for _, value := range vss[x] {
+				indicator := 0.0
+				if v == value {
+					indicator = 1
+				}
+				newRow = append(newRow,
+					tabula.NewRecordReal(indicator))
+			}
+		}
+
+		encoded.PushRow(&newRow)
+	}
+
+	encoded.RecountMajorMinor()
+
+	return encoded
+}
+
+//
+// LabelEncode will replace the value of each nominal (string) column in
+// dataset `ds`, except the class, with integer code, which is the index of
+// value in their value space, and change the column type to integer.
+// It return the mapping of column index to their value codes, which can be
+// used to encode other dataset (e.g. test set) using ApplyLabelEncode.
+//
+func LabelEncode(ds tabula.ClasetInterface) (mapping map[int]map[string]int) {
+	mapping = make(map[int]map[string]int)
+
+	for x := 0; x < ds.GetNColumn(); x++ {
+		if !isNominal(ds, x) {
+			continue
+		}
+
+		codes := make(map[string]int)
+		for code, v := range columnValueSpace(ds.GetColumn(x)) {
+			codes[v] = code
+		}
+
+		mapping[x] = codes
+	}
+
+	ApplyLabelEncode(ds, mapping)
+
+	return mapping
+}
+
+//
+// ApplyLabelEncode will replace the value of each nominal column in dataset
+// `ds` with integer code from `mapping`, the result of LabelEncode.
+// Value that is not in mapping will be replaced with -1.
+//
+func ApplyLabelEncode(ds tabula.ClasetInterface,
+	mapping map[int]map[string]int,
+) {
+	for x, codes := range mapping {
+		if x >= ds.GetNColumn() || !isNominal(ds, x) {
+			continue
+		}
+
+		col := ds.GetColumn(x)
+
+		for _, rec := range col.Records {
+			code, ok := codes[rec.String()]
+			if !ok {
+				code = -1
+			}
+			rec.SetInteger(int64(code))
+		}
+
+		col.SetType(tabula.TInteger)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/go-mining/dataset"
+	"reflect"
+	"testing"
+)
+
+var nominalConfig = []byte(`{
+	"Input"			:"unused.dat"
+,	"MaxRows"		:-1
+,	"ClassIndex"		:2
+,	"DatasetMode"		:"matrix"
+,	"InputMetadata"		:
+	[{
+		"Name"			:"x"
+	,	"Separator"		:","
+	,	"Type"			:"real"
+	},{
+		"Name"			:"color"
+	,	"Separator"		:","
+	,	"Type"			:"string"
+	,	"ValueSpace"		:
+		[
+			"red"
+		,	"green"
+		,	"blue"
+		]
+	},{
+		"Name"			:"class"
+	,	"Type"			:"string"
+	,	"ValueSpace"		:
+		[
+			"yes"
+		,	"no"
+		]
+	}]
+}`)
+
+//
+// labelConfig is the same as nominalConfig, but the color does not have value
+// space, so the codes is assigned in order of appearance in training set.
+//
+var labelConfig = []byte(`{
+	"Input"			:"unused.dat"
+,	"MaxRows"		:-1
+,	"ClassIndex"		:2
+,	"DatasetMode"		:"matrix"
+,	"InputMetadata"		:
+	[{
+		"Name"			:"x"
+	,	"Separator"		:","
+	,	"Type"			:"real"
+	},{
+		"Name"			:"color"
+	,	"Separator"		:","
+	,	"Type"			:"string"
+	},{
+		"Name"			:"class"
+	,	"Type"			:"string"
+	,	"ValueSpace"		:
+		[
+			"yes"
+		,	"no"
+		]
+	}]
+}`)
+
+var nominalData = []byte(`1.0,red,yes
+2.0,green,no
+3.0,blue,yes
+4.0,green,no
+`)
+
+func TestOneHotEncode(t *testing.T) {
+	ds, e := dataset.ReadFromBytes(nominalConfig, nominalData)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	encoded := dataset.OneHotEncode(ds)
+
+	expNames := []string{"x", "color=red", "color=green", "color=blue",
+		"class"}
+	if !reflect.DeepEqual(expNames, encoded.GetColumnsName()) {
+		t.Fatalf("Expecting columns %v, got %v", expNames,
+			encoded.GetColumnsName())
+	}
+	if encoded.GetClassIndex() != 4 {
+		t.Fatalf("Expecting class index 4, got %d",
+			encoded.GetClassIndex())
+	}
+
+	exp := []string{
+		"[1 1 0 0 yes]",
+		"[2 0 1 0 no]",
+		"[3 0 0 1 yes]",
+		"[4 0 1 0 no]",
+	}
+
+	for x, row := range *encoded.GetRows() {
+		got := fmt.Sprint(*row)
+		if got != exp[x] {
+			t.Fatalf("Expecting row %s, got %s", exp[x], got)
+		}
+	}
+}
+
+func TestLabelEncode(t *testing.T) {
+	train, e := dataset.ReadFromBytes(labelConfig, nominalData)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	test, e := dataset.ReadFromBytes(labelConfig, []byte(
+		"5.0,blue,yes\n6.0,red,no\n7.0,purple,no\n"))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	mapping := dataset.LabelEncode(train)
+
+	exp := map[int]map[string]int{
+		1: {"red": 0, "green": 1, "blue": 2},
+	}
+	if !reflect.DeepEqual(exp, mapping) {
+		t.Fatalf("Expecting mapping %v, got %v", exp, mapping)
+	}
+
+	dataset.ApplyLabelEncode(test, mapping)
+
+	var got []int64
+	for _, rec := range train.GetColumn(1).Records {
+		got = append(got, rec.Integer())
+	}
+	if !reflect.DeepEqual([]int64{0, 1, 2, 1}, got) {
+		t.Fatalf("Expecting train codes [0 1 2 1], got %v", got)
+	}
+
+	got = nil
+	for _, rec := range test.GetColumn(1).Records {
+		got = append(got, rec.Integer())
+	}
+	if !reflect.DeepEqual([]int64{2, 0, -1}, got) {
+		t.Fatalf("Expecting test codes [2 0 -1], got %v", got)
+	}
+
+	// Class column is not encoded.
+	if train.GetClassAsStrings()[0] != "yes" {
+		t.Fatalf("Expecting class is not encoded, got %v",
+			train.GetClassAsStrings())
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"compress/gzip"
+	"github.com/shuLhan/tabula"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// GzipExt file extension of gzip-compressed input.
+	GzipExt = ".gz"
+)
+
+//
+// IsGzip will return true if file `path` has gzip extension.
+//
+func IsGzip(path string) bool {
+	return strings.HasSuffix(path, GzipExt)
+}
+
+//
+// configPath return `path` relative to directory of configuration file
+// `fcfg`, if its not an absolute path.
+//
+func configPath(fcfg, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(fcfg), path)
+}
+
+//
+// gzipFile contain the gzip-compressed input file and its reader, which
+// decompress the input while its read.
+//
+type gzipFile struct {
+	f  *os.File
+	zr *gzip.Reader
+}
+
+//
+// openGzip will open the gzip-compressed file `path` for reading.
+//
+func openGzip(path string) (gz *gzipFile, e error) {
+	f, e := os.Open(path)
+	if e != nil {
+		return nil, e
+	}
+
+	zr, e := gzip.NewReader(f)
+	if e != nil {
+		f.Close()
+		return nil, e
+	}
+
+	return &gzipFile{
+		f:  f,
+		zr: zr,
+	}, nil
+}
+
+//
+// Read will read the decompressed input into `p`.
+//
+func (gz *gzipFile) Read(p []byte) (int, error) {
+	return gz.zr.Read(p)
+}
+
+//
+// Close the gzip reader and the input file.
+//
+func (gz *gzipFile) Close() error {
+	e := gz.zr.Close()
+	if e != nil {
+		gz.f.Close()
+		return e
+	}
+	return gz.f.Close()
+}
+
+//
+// gunzipRead will read the gzip-compressed input of dsv configuration `fcfg`
+// into `ds`. The input is decompressed while its parsed, so no decompressed
+// copy is written to file system.
+// The input is relative to directory of configuration file, if its not an
+// absolute path.
+//
+func gunzipRead(fcfg string, config []byte, input string,
+	ds tabula.ClasetInterface,
+) (e error) {
+	gz, e := openGzip(configPath(fcfg, input))
+	if e != nil {
+		return
+	}
+	defer gz.Close()
+
+	lr, e := newLineReader(config, gz)
+	if e != nil {
+		return
+	}
+
+	_, e = lr.read(ds, lr.MaxRows)
+	if e == io.EOF {
+		e = nil
+	}
+
+	return
+}
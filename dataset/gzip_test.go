@@ -0,0 +1,42 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset_test
+
+import (
+	"github.com/shuLhan/go-mining/dataset"
+	"github.com/shuLhan/tabula"
+	"reflect"
+	"testing"
+)
+
+func TestSimpleReadGzip(t *testing.T) {
+	ds := &tabula.Claset{}
+
+	e := dataset.SimpleRead("../testdata/iris/iris_gz.dsv", ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if ds.GetNRow() != 150 {
+		t.Fatalf("Expecting 150 rows, got %d", ds.GetNRow())
+	}
+
+	exp := readIris(t)
+
+	if !reflect.DeepEqual(exp.GetClassAsStrings(),
+		ds.GetClassAsStrings()) {
+		t.Fatalf("Expecting class %v, got %v",
+			exp.GetClassAsStrings(), ds.GetClassAsStrings())
+	}
+}
+
+func TestIsGzip(t *testing.T) {
+	if !dataset.IsGzip("phoneme.dat.gz") {
+		t.Fatal("Expecting gzip input")
+	}
+	if dataset.IsGzip("phoneme.dat") {
+		t.Fatal("Expecting non gzip input")
+	}
+}
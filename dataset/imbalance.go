@@ -0,0 +1,51 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"github.com/shuLhan/tabula"
+)
+
+//
+// ImbalanceRatio will return the majority class, minority class, and the
+// ratio of number of samples in majority class to number of samples in
+// minority class in dataset `ds`.
+//
+// A ratio much greater than one indicate that the dataset is imbalanced and
+// it may need to be resampled (e.g. using SMOTE) before training.
+// If dataset is empty, the ratio will be zero.
+//
+func ImbalanceRatio(ds tabula.ClasetInterface) (
+	majorityClass, minorityClass string, ratio float64,
+) {
+	ds.RecountMajorMinor()
+
+	majorityClass = ds.MajorityClass()
+	minorityClass = ds.MinorityClass()
+
+	vs := ds.GetClassValueSpace()
+	counts := ds.Counts()
+
+	var nmajor, nminor int
+	for x, v := range vs {
+		if x >= len(counts) {
+			break
+		}
+		if v == majorityClass {
+			nmajor = counts[x]
+		}
+		if v == minorityClass {
+			nminor = counts[x]
+		}
+	}
+
+	if nminor == 0 {
+		return majorityClass, minorityClass, 0
+	}
+
+	ratio = float64(nmajor) / float64(nminor)
+
+	return majorityClass, minorityClass, ratio
+}
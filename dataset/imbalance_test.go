@@ -0,0 +1,42 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/dataset"
+	"github.com/shuLhan/tabula"
+	"math"
+	"testing"
+)
+
+func TestImbalanceRatio(t *testing.T) {
+	ds := &tabula.Claset{}
+	_, e := dsv.SimpleRead("../testdata/phoneme/phoneme.dsv", ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	major, minor, ratio := dataset.ImbalanceRatio(ds)
+
+	fmt.Println("[dataset_test] imbalance:", major, minor, ratio)
+
+	// Phoneme contain 3818 samples in class "0" and 1586 samples in
+	// class "1".
+	if major != "0" || minor != "1" {
+		t.Fatalf("Expecting majority '0' and minority '1', got '%s'"+
+			" and '%s'", major, minor)
+	}
+	if math.Abs(ratio-3818.0/1586.0) > 1e-9 {
+		t.Fatalf("Expecting ratio %v, got %v", 3818.0/1586.0, ratio)
+	}
+
+	// Iris is balanced.
+	_, _, ratio = dataset.ImbalanceRatio(readIris(t))
+	if ratio != 1 {
+		t.Fatalf("Expecting ratio 1 on iris, got %v", ratio)
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/tabula"
+	"io"
+)
+
+var (
+	// ErrNoInputMetadata will tell you when the dsv configuration does
+	// not have input metadata.
+	ErrNoInputMetadata = errors.New("dataset: input metadata is empty")
+)
+
+//
+// lineConfig contain the options in dsv configuration that is used to parse
+// the input line by line.
+//
+type lineConfig struct {
+	ReaderConfig
+	// ClassIndex index of class column in dataset.
+	ClassIndex int `json:"ClassIndex"`
+	// MaxRows maximum number of rows to be read. If its less or equal to
+	// zero, all rows will be read.
+	MaxRows int `json:"MaxRows"`
+	// DatasetMode mode of dataset, "rows", "columns", or "matrix".
+	DatasetMode string `json:"DatasetMode"`
+	// InputMetadata contain name, type, and separator of each column.
+	InputMetadata []dsv.Metadata `json:"InputMetadata"`
+}
+
+//
+// lineReader read and parse the dataset line by line from any io.Reader,
+// using the input metadata in dsv configuration, so the input does not need
+// to be a file.
+//
+type lineReader struct {
+	lineConfig
+	// r buffered input.
+	r *bufio.Reader
+}
+
+//
+// newLineReader create new line reader that parse input `r` using dsv
+// configuration `config`. The "Input" and "Rejected" in configuration is
+// ignored.
+//
+func newLineReader(config []byte, r io.Reader) (lr *lineReader, e error) {
+	lr = &lineReader{
+		r: bufio.NewReader(r),
+	}
+
+	e = json.Unmarshal(config, &lr.lineConfig)
+	if e != nil {
+		return nil, e
+	}
+
+	if len(lr.InputMetadata) == 0 {
+		return nil, ErrNoInputMetadata
+	}
+
+	return lr, nil
+}
+
+//
+// metadataType convert the type in metadata to tabula type.
+//
+func metadataType(tipe string) int {
+	switch tipe {
+	case "integer":
+		return tabula.TInteger
+	case "real":
+		return tabula.TReal
+	}
+	return tabula.TString
+}
+
+//
+// datasetMode convert the dataset mode in configuration to tabula mode.
+//
+func datasetMode(mode string) int {
+	switch mode {
+	case "columns":
+		return tabula.DatasetModeColumns
+	case "matrix":
+		return tabula.DatasetModeMatrix
+	}
+	return tabula.DatasetModeRows
+}
+
+//
+// init will reset dataset `ds` using the columns in input metadata, and set
+// its class index.
+//
+func (lr *lineReader) init(ds tabula.ClasetInterface) error {
+	var types []int
+	var names []string
+	var vss [][]string
+
+	for _, md := range lr.InputMetadata {
+		if md.Skip {
+			continue
+		}
+		types = append(types, metadataType(md.Type))
+		names = append(names, md.Name)
+		vss = append(vss, md.ValueSpace)
+	}
+
+	ds.Init(datasetMode(lr.DatasetMode), types, names)
+
+	for x, vs := range vss {
+		ds.GetColumn(x).ValueSpace = vs
+	}
+
+	ds.SetClassIndex(lr.ClassIndex)
+
+	if lr.ClassName == "" {
+		return nil
+	}
+
+	return SetClassByName(ds, lr.ClassName)
+}
+
+//
+// parseLine will split the `line` using separator in each input metadata and
+// convert each value to its type. It will return false if the line can not
+// be parsed.
+//
+func (lr *lineReader) parseLine(line []byte) (row *tabula.Row, ok bool) {
+	row = &tabula.Row{}
+
+	for _, md := range lr.InputMetadata {
+		v := line
+		line = nil
+
+		if md.Separator != "" {
+			x := bytes.Index(v, []byte(md.Separator))
+			if x >= 0 {
+				line = v[x+len(md.Separator):]
+				v = v[:x]
+			}
+		}
+
+		if md.Skip {
+			continue
+		}
+
+		rec, e := tabula.NewRecordBy(string(bytes.TrimSpace(v)),
+			metadataType(md.Type))
+		if e != nil {
+			return nil, false
+		}
+
+		row.PushBack(rec)
+	}
+
+	return row, true
+}
+
+//
+// read will reset dataset `ds` and fill it with at most `max` rows from
+// input, or all remaining rows if `max` is less or equal to zero.
+// Empty line and line that can not be parsed is skipped.
+// At the end of input, it will return io.EOF.
+//
+func (lr *lineReader) read(ds tabula.ClasetInterface, max int) (
+	n int, e error,
+) {
+	e = lr.init(ds)
+	if e != nil {
+		return 0, e
+	}
+
+	for max <= 0 || n < max {
+		line, eRead := lr.r.ReadBytes('\n')
+
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) > 0 {
+			row, ok := lr.parseLine(line)
+			if ok {
+				ds.PushRow(row)
+				n++
+			}
+		}
+
+		if eRead != nil {
+			return n, eRead
+		}
+	}
+
+	return n, nil
+}
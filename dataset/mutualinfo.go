@@ -0,0 +1,114 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"github.com/shuLhan/tabula"
+	"math"
+	"strconv"
+)
+
+const (
+	// DefMutualInfoBins default number of equal-width bins used to
+	// discretize continuous column in MutualInformation.
+	DefMutualInfoBins = 10
+)
+
+//
+// MutualInformation will compute the mutual information, in nats, between
+// each column and the class in dataset `ds`.
+// Continuous (real) column is discretized into DefMutualInfoBins bins with
+// equal width, while other column use their value as is.
+//
+// The returned slice have the same length as number of columns, where the
+// value for class column is zero.
+//
+func MutualInformation(ds tabula.ClasetInterface) (mis []float64) {
+	ncol := ds.GetNColumn()
+	mis = make([]float64, ncol)
+
+	classIdx := ds.GetClassIndex()
+	classes := ds.GetClassAsStrings()
+	cols := ds.GetColumns()
+
+	for x := range *cols {
+		if x == classIdx {
+			continue
+		}
+
+		col := &(*cols)[x]
+
+		var values []string
+		if col.GetType() == tabula.TReal {
+			values = discretize(col.ToFloatSlice(), DefMutualInfoBins)
+		} else {
+			values = col.ToStringSlice()
+		}
+
+		mis[x] = mutualInfo(values, classes)
+	}
+
+	return mis
+}
+
+//
+// discretize will convert each value in `data` into index of bin, as string,
+// where the range of data is divided into `nbin` bins with equal width.
+//
+func discretize(data []float64, nbin int) (bins []string) {
+	min, max := minMax(data)
+	width := (max - min) / float64(nbin)
+
+	bins = make([]string, len(data))
+	for x, v := range data {
+		bin := 0
+		if width > 0 {
+			bin = int((v - min) / width)
+			if bin >= nbin {
+				bin = nbin - 1
+			}
+		}
+		bins[x] = strconv.Itoa(bin)
+	}
+
+	return bins
+}
+
+//
+// mutualInfo return the mutual information between discrete values in `a`
+// and `b`,
+//
+//	sum p(a,b) * log(p(a,b) / (p(a) * p(b)))
+//
+func mutualInfo(a, b []string) (mi float64) {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	if n == 0 {
+		return 0
+	}
+
+	countA := make(map[string]int)
+	countB := make(map[string]int)
+	countAB := make(map[[2]string]int)
+
+	for x := 0; x < n; x++ {
+		countA[a[x]]++
+		countB[b[x]]++
+		countAB[[2]string{a[x], b[x]}]++
+	}
+
+	total := float64(n)
+	for ab, c := range countAB {
+		pab := float64(c) / total
+		pa := float64(countA[ab[0]]) / total
+		pb := float64(countB[ab[1]]) / total
+
+		mi += pab * math.Log(pab/(pa*pb))
+	}
+
+	return mi
+}
@@ -0,0 +1,39 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/go-mining/dataset"
+	"testing"
+)
+
+func TestMutualInformation(t *testing.T) {
+	ds := readIris(t)
+
+	mis := dataset.MutualInformation(ds)
+
+	fmt.Println("[dataset_test] mutual information:", mis)
+
+	if len(mis) != ds.GetNColumn() {
+		t.Fatalf("Expecting %d values, got %d", ds.GetNColumn(),
+			len(mis))
+	}
+	if mis[ds.GetClassIndex()] != 0 {
+		t.Fatalf("Expecting zero on class column, got %f",
+			mis[ds.GetClassIndex()])
+	}
+
+	// Petal features (2 and 3) must rank above sepal features (0 and 1).
+	for _, petal := range []int{2, 3} {
+		for _, sepal := range []int{0, 1} {
+			if mis[petal] <= mis[sepal] {
+				t.Fatalf("Expecting petal %d (%f) above sepal"+
+					" %d (%f)", petal, mis[petal], sepal,
+					mis[sepal])
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/tabula"
+	"io/ioutil"
+)
+
+var (
+	// ErrUnknownClassName will tell you when the class name in
+	// configuration is not found in column names.
+	ErrUnknownClassName = errors.New("dataset: class name is not found" +
+		" in column names")
+)
+
+//
+// ReaderConfig contain additional options in dsv configuration file that is
+// handled by this package.
+//
+type ReaderConfig struct {
+	// Input file name in configuration. If its end with ".gz", the input
+	// will be decompressed while reading.
+	Input string `json:"Input"`
+	// ClassName if its not empty, the class index will be set to the
+	// index of column with this name, instead of using ClassIndex.
+	ClassName string `json:"ClassName"`
+}
+
+//
+// SimpleRead will read dataset using dsv configuration file `fcfg` into
+// `ds`, and resolve the "ClassName" option in configuration, if its set, to
+// class index.
+// If the input file is compressed with gzip, which detected by ".gz"
+// extension, it will be decompressed while parsed, without temporary file.
+//
+func SimpleRead(fcfg string, ds tabula.ClasetInterface) (e error) {
+	config, e := ioutil.ReadFile(fcfg)
+	if e != nil {
+		return
+	}
+
+	rcfg := ReaderConfig{}
+
+	e = json.Unmarshal(config, &rcfg)
+	if e != nil {
+		return
+	}
+
+	if IsGzip(rcfg.Input) {
+		return gunzipRead(fcfg, config, rcfg.Input, ds)
+	}
+
+	_, e = dsv.SimpleRead(fcfg, ds)
+	if e != nil {
+		return
+	}
+
+	if rcfg.ClassName == "" {
+		return nil
+	}
+
+	return SetClassByName(ds, rcfg.ClassName)
+}
+
+//
+// SetClassByName will set the class index in dataset `ds` to the index of
+// column with name `name`. It will return ErrUnknownClassName if no column
+// has that name.
+//
+func SetClassByName(ds tabula.ClasetInterface, name string) error {
+	for x, colName := range ds.GetColumnsName() {
+		if colName == name {
+			ds.SetClassIndex(x)
+			return nil
+		}
+	}
+
+	return ErrUnknownClassName
+}
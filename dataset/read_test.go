@@ -0,0 +1,39 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset_test
+
+import (
+	"github.com/shuLhan/go-mining/dataset"
+	"github.com/shuLhan/tabula"
+	"reflect"
+	"testing"
+)
+
+func TestSimpleReadClassName(t *testing.T) {
+	ds := &tabula.Claset{}
+
+	e := dataset.SimpleRead("../testdata/iris/iris_classname.dsv", ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	exp := readIris(t)
+
+	if exp.GetClassIndex() != ds.GetClassIndex() {
+		t.Fatalf("Expecting class index %d, got %d",
+			exp.GetClassIndex(), ds.GetClassIndex())
+	}
+	if !reflect.DeepEqual(exp.GetClassAsStrings(),
+		ds.GetClassAsStrings()) {
+		t.Fatalf("Expecting class %v, got %v",
+			exp.GetClassAsStrings(), ds.GetClassAsStrings())
+	}
+
+	e = dataset.SetClassByName(ds, "unknown")
+	if e != dataset.ErrUnknownClassName {
+		t.Fatalf("Expecting error %v, got %v",
+			dataset.ErrUnknownClassName, e)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"github.com/shuLhan/tabula"
+	"math"
+)
+
+//
+// StandardizeColumns will transform each continuous column, except class,
+// in dataset `ds` into z-score,
+//
+//	(x - mean) / std
+//
+// and return the mean and standard deviation of each column. Non-continuous
+// column and class column have zero mean and zero standard deviation.
+// If standard deviation of column is zero, the column values is only centered
+// using their mean.
+//
+func StandardizeColumns(ds tabula.ClasetInterface) (means, stds []float64) {
+	ncol := ds.GetNColumn()
+	means = make([]float64, ncol)
+	stds = make([]float64, ncol)
+
+	classIdx := ds.GetClassIndex()
+	cols := ds.GetColumns()
+
+	for x := range *cols {
+		col := &(*cols)[x]
+
+		if x == classIdx || col.GetType() != tabula.TReal {
+			continue
+		}
+
+		means[x], stds[x] = meanStd(col.ToFloatSlice())
+	}
+
+	ApplyStandardize(ds, means, stds)
+
+	return means, stds
+}
+
+//
+// ApplyStandardize will transform each continuous column, except class, in
+// dataset `ds` using `means` and `stds` from StandardizeColumns.
+// This can be used to transform the test set using parameters from
+// training set.
+//
+func ApplyStandardize(ds tabula.ClasetInterface, means, stds []float64) {
+	classIdx := ds.GetClassIndex()
+	cols := ds.GetColumns()
+
+	for x := range *cols {
+		col := &(*cols)[x]
+
+		if x == classIdx || col.GetType() != tabula.TReal {
+			continue
+		}
+		if x >= len(means) || x >= len(stds) {
+			break
+		}
+
+		for _, rec := range col.Records {
+			v := rec.Float() - means[x]
+			if stds[x] != 0 {
+				v = v / stds[x]
+			}
+			rec.SetFloat(v)
+		}
+	}
+}
+
+//
+// ScaleMinMax will rescale each continuous column, except class, in dataset
+// `ds` into range [0, 1] using,
+//
+//	(x - min) / (max - min)
+//
+// and return the minimum and maximum value of each column. Non-continuous
+// column and class column have zero minimum and maximum.
+// If minimum and maximum value of column is equal, all values in column will
+// be set to zero.
+//
+func ScaleMinMax(ds tabula.ClasetInterface) (min, max []float64) {
+	ncol := ds.GetNColumn()
+	min = make([]float64, ncol)
+	max = make([]float64, ncol)
+
+	classIdx := ds.GetClassIndex()
+	cols := ds.GetColumns()
+
+	for x := range *cols {
+		col := &(*cols)[x]
+
+		if x == classIdx || col.GetType() != tabula.TReal {
+			continue
+		}
+
+		min[x], max[x] = minMax(col.ToFloatSlice())
+	}
+
+	ApplyMinMax(ds, min, max)
+
+	return min, max
+}
+
+//
+// ApplyMinMax will rescale each continuous column, except class, in dataset
+// `ds` using `min` and `max` values from ScaleMinMax.
+// Value from new data that is outside of the original range will be scaled
+// outside of [0, 1].
+//
+func ApplyMinMax(ds tabula.ClasetInterface, min, max []float64) {
+	classIdx := ds.GetClassIndex()
+	cols := ds.GetColumns()
+
+	for x := range *cols {
+		col := &(*cols)[x]
+
+		if x == classIdx || col.GetType() != tabula.TReal {
+			continue
+		}
+		if x >= len(min) || x >= len(max) {
+			break
+		}
+
+		scale := max[x] - min[x]
+
+		for _, rec := range col.Records {
+			v := 0.0
+			if scale != 0 {
+				v = (rec.Float() - min[x]) / scale
+			}
+			rec.SetFloat(v)
+		}
+	}
+}
+
+//
+// minMax return the minimum and maximum value in `data`.
+//
+func minMax(data []float64) (min, max float64) {
+	if len(data) == 0 {
+		return
+	}
+
+	min = data[0]
+	max = data[0]
+	for _, v := range data[1:] {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	return
+}
+
+//
+// meanStd return the mean and population standard deviation of `data`.
+//
+func meanStd(data []float64) (mean, std float64) {
+	n := float64(len(data))
+	if n == 0 {
+		return
+	}
+
+	for _, v := range data {
+		mean += v
+	}
+	mean = mean / n
+
+	for _, v := range data {
+		d := v - mean
+		std += d * d
+	}
+	std = math.Sqrt(std / n)
+
+	return
+}
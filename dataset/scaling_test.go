@@ -0,0 +1,106 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/dataset"
+	"github.com/shuLhan/tabula"
+	"math"
+	"testing"
+)
+
+const (
+	fIris = "../testdata/iris/iris.dsv"
+)
+
+func readIris(t *testing.T) *tabula.Claset {
+	ds := &tabula.Claset{}
+	_, e := dsv.SimpleRead(fIris, ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+	return ds
+}
+
+func TestStandardizeColumns(t *testing.T) {
+	ds := readIris(t)
+
+	means, stds := dataset.StandardizeColumns(ds)
+
+	fmt.Println("[dataset_test] means:", means)
+	fmt.Println("[dataset_test] stds :", stds)
+
+	for x, col := range *ds.GetColumns() {
+		if x == ds.GetClassIndex() {
+			continue
+		}
+
+		var mean, std float64
+		data := col.ToFloatSlice()
+		for _, v := range data {
+			mean += v
+		}
+		mean /= float64(len(data))
+		for _, v := range data {
+			std += (v - mean) * (v - mean)
+		}
+		std = math.Sqrt(std / float64(len(data)))
+
+		if math.Abs(mean) > 1e-9 {
+			t.Fatalf("Column %d: expecting mean 0, got %f", x, mean)
+		}
+		if math.Abs(std-1) > 1e-9 {
+			t.Fatalf("Column %d: expecting std 1, got %f", x, std)
+		}
+	}
+}
+
+func TestScaleMinMax(t *testing.T) {
+	ds := readIris(t)
+
+	min, max := dataset.ScaleMinMax(ds)
+
+	fmt.Println("[dataset_test] min:", min)
+	fmt.Println("[dataset_test] max:", max)
+
+	for x, col := range *ds.GetColumns() {
+		if x == ds.GetClassIndex() {
+			continue
+		}
+
+		data := col.ToFloatSlice()
+		gotMin, gotMax := data[0], data[0]
+		for _, v := range data {
+			gotMin = math.Min(gotMin, v)
+			gotMax = math.Max(gotMax, v)
+		}
+
+		if gotMin != 0 || gotMax != 1 {
+			t.Fatalf("Column %d: expecting range [0,1], got [%f,%f]",
+				x, gotMin, gotMax)
+		}
+	}
+
+	// Applying the same scaling to the original data must give the same
+	// result.
+	test := readIris(t)
+	dataset.ApplyMinMax(test, min, max)
+
+	for x, col := range *test.GetColumns() {
+		if x == test.GetClassIndex() {
+			continue
+		}
+		exp := ds.GetColumn(x).ToFloatSlice()
+		got := col.ToFloatSlice()
+		for y := range exp {
+			if math.Abs(exp[y]-got[y]) > 1e-12 {
+				t.Fatalf("Column %d row %d: expecting %f, got %f",
+					x, y, exp[y], got[y])
+			}
+		}
+	}
+}
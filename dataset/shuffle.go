@@ -0,0 +1,40 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"github.com/shuLhan/tabula"
+	"math/rand"
+)
+
+//
+// Shuffle will shuffle the rows in dataset `ds` in place using Fisher-Yates
+// algorithm, keeping the values in each row intact.
+// The `rng` is used as random generator, so the order can be reproduced
+// using the same seed. If `rng` is nil, the global random generator is used.
+//
+func Shuffle(ds tabula.DatasetInterface, rng *rand.Rand) {
+	intn := rand.Intn
+	if rng != nil {
+		intn = rng.Intn
+	}
+
+	nrow := ds.GetNRow()
+	if nrow <= 1 {
+		return
+	}
+
+	perm := make([]int, nrow)
+	for x := range perm {
+		perm[x] = x
+	}
+
+	for x := nrow - 1; x > 0; x-- {
+		y := intn(x + 1)
+		perm[x], perm[y] = perm[y], perm[x]
+	}
+
+	tabula.SortColumnsByIndex(ds, perm)
+}
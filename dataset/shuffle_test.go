@@ -0,0 +1,62 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/go-mining/dataset"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestShuffle(t *testing.T) {
+	orig := readIris(t)
+
+	var exp []string
+	for _, row := range *orig.GetRows() {
+		exp = append(exp, fmt.Sprint(*row))
+	}
+
+	shuffled := make([][]string, 2)
+
+	for x := range shuffled {
+		ds := readIris(t)
+
+		dataset.Shuffle(ds, rand.New(rand.NewSource(7)))
+
+		for _, row := range *ds.GetRows() {
+			shuffled[x] = append(shuffled[x], fmt.Sprint(*row))
+		}
+
+		// Class column must follow the rows.
+		classes := ds.GetClassAsStrings()
+		for y, row := range *ds.GetRows() {
+			got := (*row)[ds.GetClassIndex()].String()
+			if got != classes[y] {
+				t.Fatalf("Expecting class %s on row %d, got %s",
+					classes[y], y, got)
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(shuffled[0], shuffled[1]) {
+		t.Fatal("Expecting shuffle with the same seed is reproducible")
+	}
+	if reflect.DeepEqual(exp, shuffled[0]) {
+		t.Fatal("Expecting rows order is changed")
+	}
+
+	got := make([]string, len(shuffled[0]))
+	copy(got, shuffled[0])
+
+	sort.Strings(exp)
+	sort.Strings(got)
+
+	if !reflect.DeepEqual(exp, got) {
+		t.Fatal("Expecting shuffle preserve all rows")
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"github.com/shuLhan/tabula"
+	"math/rand"
+)
+
+//
+// TrainTestSplit will split the rows in dataset `ds` randomly into training
+// set and test set. Number of rows in training set is `trainFraction` of
+// number of rows in `ds`, the rest of rows is put into test set.
+//
+// If `stratify` is true, the split is done on each class, so the proportion
+// of each class in training and test set is preserved as in `ds`.
+//
+// The `seed` is used to initialize the random generator, so the split can be
+// reproduced using the same seed.
+// Both of training and test set have the same class index as `ds`.
+// Each row is cloned, so modifying the returned sets will not change `ds`.
+//
+func TrainTestSplit(ds tabula.ClasetInterface, trainFraction float64,
+	seed int64, stratify bool,
+) (
+	train, test tabula.ClasetInterface,
+) {
+	nrow := ds.GetNRow()
+	rng := rand.New(rand.NewSource(seed))
+	isTrain := make([]bool, nrow)
+
+	if stratify {
+		classes := ds.GetClassAsStrings()
+		groups := make(map[string][]int)
+
+		for x, class := range classes {
+			groups[class] = append(groups[class], x)
+		}
+
+		// Iterate using value space to keep the random sequence
+		// reproducible.
+		for _, class := range ds.GetClassValueSpace() {
+			markTrain(isTrain, groups[class], trainFraction, rng)
+			delete(groups, class)
+		}
+
+		// Class values that is not in value space.
+		for x, class := range classes {
+			ids, ok := groups[class]
+			if !ok {
+				continue
+			}
+			if x == ids[0] {
+				markTrain(isTrain, ids, trainFraction, rng)
+			}
+		}
+	} else {
+		ids := make([]int, nrow)
+		for x := range ids {
+			ids[x] = x
+		}
+		markTrain(isTrain, ids, trainFraction, rng)
+	}
+
+	train = newSplitSet(ds)
+	test = newSplitSet(ds)
+
+	for _, idx := range rng.Perm(nrow) {
+		row := ds.GetRow(idx).Clone()
+		if isTrain[idx] {
+			train.PushRow(row)
+		} else {
+			test.PushRow(row)
+		}
+	}
+
+	train.RecountMajorMinor()
+	test.RecountMajorMinor()
+
+	return train, test
+}
+
+//
+// markTrain will randomly pick `trainFraction` of row indices `ids` and mark
+// it as training row in `isTrain`.
+//
+func markTrain(isTrain []bool, ids []int, trainFraction float64,
+	rng *rand.Rand,
+) {
+	n := len(ids)
+
+	ntrain := int(float64(n) * trainFraction)
+	if ntrain < 0 {
+		ntrain = 0
+	} else if ntrain > n {
+		ntrain = n
+	}
+
+	for x, y := range rng.Perm(n) {
+		if x >= ntrain {
+			break
+		}
+		isTrain[ids[y]] = true
+	}
+}
+
+//
+// newSplitSet will create an empty dataset with the same metadata and class
+// index as `ds`.
+//
+func newSplitSet(ds tabula.ClasetInterface) (set tabula.ClasetInterface) {
+	set = ds.Clone().(tabula.ClasetInterface)
+	set.SetClassIndex(ds.GetClassIndex())
+
+	return set
+}
@@ -0,0 +1,108 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/dataset"
+	"github.com/shuLhan/tabula"
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func rowsAsStrings(ds tabula.DatasetInterface) (rows []string) {
+	for _, row := range *ds.GetRows() {
+		rows = append(rows, fmt.Sprint(*row))
+	}
+	return
+}
+
+func TestTrainTestSplit(t *testing.T) {
+	ds := readIris(t)
+	nrow := ds.GetNRow()
+
+	train, test := dataset.TrainTestSplit(ds, 0.7, 7, false)
+
+	if train.GetNRow() != 105 {
+		t.Fatalf("Expecting 105 rows in training set, got %d",
+			train.GetNRow())
+	}
+	if train.GetNRow()+test.GetNRow() != nrow {
+		t.Fatalf("Expecting %d rows in total, got %d", nrow,
+			train.GetNRow()+test.GetNRow())
+	}
+
+	if train.GetClassIndex() != ds.GetClassIndex() {
+		t.Fatalf("Expecting train class index %d, got %d",
+			ds.GetClassIndex(), train.GetClassIndex())
+	}
+	if test.GetClassIndex() != ds.GetClassIndex() {
+		t.Fatalf("Expecting test class index %d, got %d",
+			ds.GetClassIndex(), test.GetClassIndex())
+	}
+
+	// Each row in dataset must be either in training or test set.
+	exp := rowsAsStrings(ds)
+	got := append(rowsAsStrings(train), rowsAsStrings(test)...)
+
+	sort.Strings(exp)
+	sort.Strings(got)
+
+	if !reflect.DeepEqual(exp, got) {
+		t.Fatal("Expecting train and test set is disjoint and" +
+			" contain all rows")
+	}
+
+	// The same seed must produce the same split.
+	train2, _ := dataset.TrainTestSplit(ds, 0.7, 7, false)
+
+	if !reflect.DeepEqual(rowsAsStrings(train), rowsAsStrings(train2)) {
+		t.Fatal("Expecting split with the same seed is reproducible")
+	}
+}
+
+func classFraction(ds tabula.ClasetInterface, class string) float64 {
+	n := 0
+	classes := ds.GetClassAsStrings()
+	for _, v := range classes {
+		if v == class {
+			n++
+		}
+	}
+	return float64(n) / float64(len(classes))
+}
+
+func TestTrainTestSplitStratify(t *testing.T) {
+	ds := &tabula.Claset{}
+	_, e := dsv.SimpleRead("../testdata/phoneme/phoneme.dsv", ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	_, minority, _ := dataset.ImbalanceRatio(ds)
+	exp := classFraction(ds, minority)
+
+	train, test := dataset.TrainTestSplit(ds, 0.7, 7, true)
+
+	if train.GetNRow()+test.GetNRow() != ds.GetNRow() {
+		t.Fatalf("Expecting %d rows in total, got %d", ds.GetNRow(),
+			train.GetNRow()+test.GetNRow())
+	}
+
+	for _, set := range []tabula.ClasetInterface{train, test} {
+		got := classFraction(set, minority)
+
+		fmt.Printf("[dataset_test] minority fraction: %f, got %f\n",
+			exp, got)
+
+		if math.Abs(exp-got) > 0.005 {
+			t.Fatalf("Expecting minority fraction %f, got %f",
+				exp, got)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/tabula"
+	"io"
+	"io/ioutil"
+	"math/rand"
+)
+
+const (
+	// DefBatchSize default number of rows read in each batch.
+	DefBatchSize = 1000
+)
+
+var (
+	// ErrInvalidReservoir will tell you when size or number of reservoir
+	// is less or equal to zero.
+	ErrInvalidReservoir = errors.New("dataset: size and number of" +
+		" reservoir must be greater than zero")
+)
+
+//
+// StreamReader read dataset from file in batches, so only one batch of rows
+// is kept in memory at a time.
+//
+type StreamReader struct {
+	// BatchSize maximum number of rows in each batch.
+	BatchSize int
+
+	// reader for input file.
+	reader *dsv.Reader
+	// gz is the gzip-compressed input file, if input end with ".gz".
+	gz *gzipFile
+	// lines parse the gzip-compressed input, instead of reader.
+	lines *lineReader
+	// batch contain the rows from the last read.
+	batch *tabula.Claset
+	// nrow number of rows that has been read.
+	nrow int
+	// isEOF will be true if the end of input has been reached.
+	isEOF bool
+}
+
+//
+// NewStreamReader create new stream reader using dsv configuration file
+// `config`, where each batch contain maximum `batchSize` rows. If
+// `batchSize` is less or equal to zero, it will be set to DefBatchSize.
+// If the input is compressed with gzip, it will be decompressed while read.
+//
+func NewStreamReader(config string, batchSize int) (
+	sr *StreamReader, e error,
+) {
+	if batchSize <= 0 {
+		batchSize = DefBatchSize
+	}
+
+	sr = &StreamReader{
+		BatchSize: batchSize,
+		batch:     &tabula.Claset{},
+	}
+
+	cfg, e := ioutil.ReadFile(config)
+	if e != nil {
+		return nil, e
+	}
+
+	rcfg := ReaderConfig{}
+
+	e = json.Unmarshal(cfg, &rcfg)
+	if e != nil {
+		return nil, e
+	}
+
+	if IsGzip(rcfg.Input) {
+		sr.gz, e = openGzip(configPath(config, rcfg.Input))
+		if e != nil {
+			return nil, e
+		}
+
+		sr.lines, e = newLineReader(cfg, sr.gz)
+		if e != nil {
+			sr.gz.Close()
+			return nil, e
+		}
+
+		return sr, nil
+	}
+
+	sr.reader, e = dsv.NewReader(config, sr.batch)
+	if e != nil {
+		return nil, e
+	}
+
+	sr.reader.SetMaxRows(batchSize)
+
+	return sr, nil
+}
+
+//
+// NRow return number of rows that has been read.
+//
+func (sr *StreamReader) NRow() int {
+	return sr.nrow
+}
+
+//
+// Next will read the next batch of rows and return it. The returned batch is
+// reused on the next call, so the caller must copy the rows that need to be
+// kept.
+// At the end of input, it will return io.EOF, with the last rows in batch if
+// its not empty.
+//
+func (sr *StreamReader) Next() (batch tabula.ClasetInterface, e error) {
+	if sr.isEOF {
+		return nil, io.EOF
+	}
+
+	var n int
+
+	if sr.lines != nil {
+		n, e = sr.lines.read(sr.batch, sr.BatchSize)
+	} else {
+		n, e = dsv.Read(sr.reader)
+	}
+
+	sr.nrow += n
+
+	if e == io.EOF {
+		sr.isEOF = true
+	} else if e != nil {
+		return nil, e
+	}
+
+	return sr.batch, e
+}
+
+//
+// ReservoirSample will read all remaining rows in a single pass and return
+// `k` independent random samples, where each sample contain `n` rows
+// selected without replacement using reservoir sampling (Algorithm R by
+// Vitter), as in ReservoirSample function.
+// Only `k` times `n` rows and one batch is kept in memory at a time.
+// If number of rows is less than `n`, each sample will contain all rows.
+// The `rng` is used as random generator, so the samples can be reproduced
+// using the same seed. If `rng` is nil, the global random generator is used.
+//
+// Each sample can be used to build one tree in forest, as a bootstrap
+// (pasting) sample of the whole dataset.
+//
+func (sr *StreamReader) ReservoirSample(n, k int, rng *rand.Rand) (
+	samples []tabula.ClasetInterface, e error,
+) {
+	if n <= 0 || k <= 0 {
+		return nil, ErrInvalidReservoir
+	}
+
+	reservoirs := make([]*reservoir, k)
+	for x := range reservoirs {
+		reservoirs[x] = newReservoir(n, rng)
+	}
+
+	for {
+		batch, eRead := sr.Next()
+		if eRead != nil && eRead != io.EOF {
+			return nil, eRead
+		}
+
+		if batch != nil {
+			for _, row := range *batch.GetRows() {
+				for _, r := range reservoirs {
+					r.add(row)
+				}
+			}
+		}
+
+		if eRead == io.EOF {
+			break
+		}
+	}
+
+	samples = make([]tabula.ClasetInterface, k)
+	for x, r := range reservoirs {
+		samples[x] = sr.batch.Clone().(tabula.ClasetInterface)
+
+		for _, row := range r.rows {
+			samples[x].PushRow(row)
+		}
+	}
+
+	return samples, nil
+}
+
+//
+// ReservoirSample select `k` rows from `rows` randomly without replacement
+// using reservoir sampling (Algorithm R by Vitter), where each row have the
+// same probability k/n to be selected.
+// The `rng` is used as random generator, so the selection can be
+// reproduced using the same seed. If `rng` is nil, the global random
+// generator is used.
+// If `k` is greater or equal to number of rows, all rows will be returned.
+//
+func ReservoirSample(rows *tabula.Rows, k int, rng *rand.Rand) *tabula.Rows {
+	r := newReservoir(k, rng)
+
+	for _, row := range *rows {
+		r.add(row)
+	}
+
+	return &r.rows
+}
+
+//
+// reservoir contain at most `k` rows that is selected randomly from all rows
+// that has been added, using Algorithm R.
+//
+type reservoir struct {
+	// k maximum number of rows in reservoir.
+	k int
+	// seen number of rows that has been added.
+	seen int
+	// rows contain the selected rows.
+	rows tabula.Rows
+	// intn return random number in [0,n).
+	intn func(n int) int
+}
+
+//
+// newReservoir create new reservoir with size `k`, using `rng` as random
+// generator, or the global random generator if its nil.
+//
+func newReservoir(k int, rng *rand.Rand) (r *reservoir) {
+	if k < 0 {
+		k = 0
+	}
+
+	r = &reservoir{
+		k:    k,
+		rows: make(tabula.Rows, 0, k),
+		intn: rand.Intn,
+	}
+	if rng != nil {
+		r.intn = rng.Intn
+	}
+
+	return r
+}
+
+//
+// add will put the `row` into reservoir if its not full, otherwise replace
+// a random row in reservoir with probability k/seen.
+//
+func (r *reservoir) add(row *tabula.Row) {
+	r.seen++
+
+	if len(r.rows) < r.k {
+		r.rows = append(r.rows, row)
+		return
+	}
+
+	x := r.intn(r.seen)
+	if x < r.k {
+		r.rows[x] = row
+	}
+}
+
+//
+// Close the input file.
+//
+func (sr *StreamReader) Close() error {
+	if sr.gz != nil {
+		return sr.gz.Close()
+	}
+	return sr.reader.Close()
+}
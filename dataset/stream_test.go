@@ -0,0 +1,268 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset_test
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/go-mining/classifier/rf"
+	"github.com/shuLhan/go-mining/dataset"
+	"github.com/shuLhan/tabula"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+const streamConfig = `{
+	"Input"			:"large.dat"
+,	"Rejected"		:"large.rej"
+,	"ClassMetadataIndex"	:2
+,	"ClassIndex"		:2
+,	"DatasetMode"		:"matrix"
+,	"InputMetadata"		:
+	[{
+		"Name"		:"x"
+	,	"Separator"	:","
+	,	"Type"		:"real"
+	},{
+		"Name"		:"y"
+	,	"Separator"	:","
+	,	"Type"		:"real"
+	},{
+		"Name"		:"class"
+	,	"Type"		:"string"
+	,	"ValueSpace"	:["0","1"]
+	}]
+}`
+
+//
+// createLargeFile will create dataset with `nrow` random points, where the
+// class is "1" if x + y > 1, and return the path to configuration file.
+//
+func createLargeFile(t *testing.T, dir string, nrow int) string {
+	fdat, e := os.Create(filepath.Join(dir, "large.dat"))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	w := bufio.NewWriter(fdat)
+	for x := 0; x < nrow; x++ {
+		a, b := rand.Float64(), rand.Float64()
+		class := 0
+		if a+b > 1 {
+			class = 1
+		}
+		fmt.Fprintf(w, "%f,%f,%d\n", a, b, class)
+	}
+
+	e = w.Flush()
+	if e != nil {
+		t.Fatal(e)
+	}
+	e = fdat.Close()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	fcfg := filepath.Join(dir, "large.dsv")
+	e = ioutil.WriteFile(fcfg, []byte(streamConfig), 0600)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	return fcfg
+}
+
+func heapAlloc() uint64 {
+	var ms runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&ms)
+	return ms.HeapAlloc
+}
+
+func TestStreamReaderReservoir(t *testing.T) {
+	const (
+		nrow      = 200000
+		batchSize = 1000
+		nsample   = 500
+		ntree     = 10
+		// maxHeap is the maximum heap growth allowed while reading,
+		// far below the memory needed to load all rows.
+		maxHeap = 32 << 20
+	)
+
+	dir, e := ioutil.TempDir("", "stream")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	fcfg := createLargeFile(t, dir, nrow)
+
+	sr, e := dataset.NewStreamReader(fcfg, batchSize)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	before := heapAlloc()
+
+	bags, e := sr.ReservoirSample(nsample, ntree,
+		rand.New(rand.NewSource(1)))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	after := heapAlloc()
+
+	e = sr.Close()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	fmt.Printf("[dataset_test] rows: %d, heap before: %d, after: %d\n",
+		sr.NRow(), before, after)
+
+	if sr.NRow() != nrow {
+		t.Fatalf("Expecting %d rows, got %d", nrow, sr.NRow())
+	}
+	if after > before && after-before > maxHeap {
+		t.Fatalf("Expecting heap growth less than %d, got %d",
+			maxHeap, after-before)
+	}
+
+	for _, bag := range bags {
+		if bag.GetNRow() != nsample {
+			t.Fatalf("Expecting bag size %d, got %d", nsample,
+				bag.GetNRow())
+		}
+	}
+
+	forest := rf.Runtime{
+		Runtime: classifier.Runtime{
+			OOBStatsFile: filepath.Join(dir, "large.oob"),
+			StatFile:     filepath.Join(dir, "large.stat"),
+		},
+	}
+
+	e = forest.BuildFromBags(bags)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// Test the forest using the first batch of dataset.
+	sr, e = dataset.NewStreamReader(fcfg, batchSize)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	testset, e := sr.Next()
+	if e != nil && e != io.EOF {
+		t.Fatal(e)
+	}
+
+	_, cm, _ := forest.ClassifySet(testset, nil)
+
+	e = sr.Close()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if cm.GetTrueRate() < 0.9 {
+		t.Fatalf("Expecting accuracy >= 0.9, got %f", cm.GetTrueRate())
+	}
+}
+
+func TestStreamReaderGzip(t *testing.T) {
+	sr, e := dataset.NewStreamReader("../testdata/iris/iris_gz.dsv", 40)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	var classes []string
+
+	for {
+		batch, e := sr.Next()
+		if e != nil && e != io.EOF {
+			t.Fatal(e)
+		}
+		if batch != nil {
+			if batch.GetNRow() > 40 {
+				t.Fatalf("Expecting batch size <= 40, got %d",
+					batch.GetNRow())
+			}
+			classes = append(classes, batch.GetClassAsStrings()...)
+		}
+		if e == io.EOF {
+			break
+		}
+	}
+
+	e = sr.Close()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if sr.NRow() != 150 {
+		t.Fatalf("Expecting 150 rows, got %d", sr.NRow())
+	}
+
+	exp := readIris(t).GetClassAsStrings()
+
+	if !reflect.DeepEqual(exp, classes) {
+		t.Fatalf("Expecting class %v, got %v", exp, classes)
+	}
+}
+
+func TestReservoirSample(t *testing.T) {
+	const (
+		n      = 20
+		k      = 5
+		trials = 20000
+	)
+
+	rows := make(tabula.Rows, n)
+	for x := range rows {
+		rows[x] = &tabula.Row{tabula.NewRecordInt(int64(x))}
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	counts := make([]int, n)
+
+	for x := 0; x < trials; x++ {
+		picks := dataset.ReservoirSample(&rows, k, rng)
+
+		if picks.Len() != k {
+			t.Fatalf("Expecting %d rows, got %d", k, picks.Len())
+		}
+
+		for _, row := range *picks {
+			counts[(*row)[0].Integer()]++
+		}
+	}
+
+	exp := float64(trials) * k / n
+
+	fmt.Println("[dataset_test] reservoir counts:", counts)
+
+	for x, count := range counts {
+		if math.Abs(float64(count)-exp)/exp > 0.1 {
+			t.Fatalf("Row %d: expecting picked around %f times,"+
+				" got %d", x, exp, count)
+		}
+	}
+
+	// k greater than number of rows return all rows.
+	picks := dataset.ReservoirSample(&rows, n+1, rng)
+	if picks.Len() != n {
+		t.Fatalf("Expecting %d rows, got %d", n, picks.Len())
+	}
+}
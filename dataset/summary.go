@@ -0,0 +1,73 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"github.com/shuLhan/tabula"
+)
+
+//
+// FeatureSummary contain the summary statistic of one feature (column) in
+// dataset.
+//
+type FeatureSummary struct {
+	// ColumnIndex index of column in dataset.
+	ColumnIndex int
+	// ColumnName name of column in dataset.
+	ColumnName string
+	// IsContinu will be true if column type is real.
+	IsContinu bool
+	// Min minimum value of continuous column.
+	Min float64
+	// Max maximum value of continuous column.
+	Max float64
+	// Mean mean value of continuous column.
+	Mean float64
+	// Std population standard deviation of continuous column.
+	Std float64
+	// Counts number of occurrence of each value in discrete column.
+	Counts map[string]int
+}
+
+//
+// Summarize will return the summary of each feature in dataset `ds`,
+// excluding the class column, in the same order as the columns.
+// Continuous (real) column will have their minimum, maximum, mean, and
+// standard deviation value; while discrete column will have the number of
+// occurrence of each value.
+//
+func Summarize(ds tabula.ClasetInterface) (summaries []FeatureSummary) {
+	classIdx := ds.GetClassIndex()
+	cols := ds.GetColumns()
+
+	for x := range *cols {
+		if x == classIdx {
+			continue
+		}
+
+		col := &(*cols)[x]
+
+		summary := FeatureSummary{
+			ColumnIndex: x,
+			ColumnName:  col.GetName(),
+			IsContinu:   col.GetType() == tabula.TReal,
+		}
+
+		if summary.IsContinu {
+			data := col.ToFloatSlice()
+			summary.Min, summary.Max = minMax(data)
+			summary.Mean, summary.Std = meanStd(data)
+		} else {
+			summary.Counts = make(map[string]int)
+			for _, v := range col.ToStringSlice() {
+				summary.Counts[v]++
+			}
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries
+}
@@ -0,0 +1,40 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/go-mining/dataset"
+	"math"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	ds := readIris(t)
+
+	summaries := dataset.Summarize(ds)
+
+	fmt.Printf("[dataset_test] summaries: %+v\n", summaries)
+
+	// Iris have four features, the class is excluded.
+	if len(summaries) != 4 {
+		t.Fatalf("Expecting 4 summaries, got %d", len(summaries))
+	}
+
+	sepalLength := summaries[0]
+
+	if !sepalLength.IsContinu {
+		t.Fatal("Expecting sepal length is continuous")
+	}
+	// The mean of sepal length in iris is 5.843.
+	if math.Abs(sepalLength.Mean-5.843) > 0.001 {
+		t.Fatalf("Expecting sepal length mean 5.843, got %f",
+			sepalLength.Mean)
+	}
+	if sepalLength.Min != 4.3 || sepalLength.Max != 7.9 {
+		t.Fatalf("Expecting sepal length in [4.3, 7.9], got [%f, %f]",
+			sepalLength.Min, sepalLength.Max)
+	}
+}
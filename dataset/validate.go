@@ -0,0 +1,112 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"fmt"
+	"github.com/shuLhan/tabula"
+	"strconv"
+)
+
+const (
+	// WarnNumericString will be set in Warning when column type is
+	// string but all of their values is numeric.
+	WarnNumericString = "string column contain only numeric values"
+	// WarnNonNumeric will be set in Warning when column type is integer
+	// or real but some of their values is not numeric.
+	WarnNonNumeric = "numeric column contain non numeric values"
+)
+
+//
+// Warning contain the column that may have wrong type in metadata.
+//
+type Warning struct {
+	// ColumnIndex index of column in dataset.
+	ColumnIndex int
+	// ColumnName name of column in dataset.
+	ColumnName string
+	// Msg the warning message.
+	Msg string
+}
+
+//
+// String return the warning as text.
+//
+func (w Warning) String() string {
+	return fmt.Sprintf("dataset: column %d (%s): %s", w.ColumnIndex,
+		w.ColumnName, w.Msg)
+}
+
+//
+// ValidateTypes will scan the values in each column, except class, in
+// dataset `ds` and return warning for each column that may have wrong type
+// in metadata: a string column where all of their values can be parsed as
+// number, or a numeric column where some of their values can not be parsed
+// as number.
+//
+// Classifier like CART compute the split of continuous and discrete column
+// differently based on column type, so wrong type in metadata will produce
+// a different tree without any error.
+//
+func ValidateTypes(ds tabula.ClasetInterface) (warns []Warning) {
+	classIdx := ds.GetClassIndex()
+	cols := ds.GetColumns()
+
+	for x := range *cols {
+		col := &(*cols)[x]
+
+		if x == classIdx || col.Len() <= 0 {
+			continue
+		}
+
+		var msg string
+
+		switch col.GetType() {
+		case tabula.TString:
+			if isAllNumeric(col.Records) {
+				msg = WarnNumericString
+			}
+		case tabula.TInteger, tabula.TReal:
+			if !isAllNumeric(col.Records) {
+				msg = WarnNonNumeric
+			}
+		}
+
+		if msg == "" {
+			continue
+		}
+
+		warns = append(warns, Warning{
+			ColumnIndex: x,
+			ColumnName:  col.GetName(),
+			Msg:         msg,
+		})
+	}
+
+	return warns
+}
+
+//
+// isAllNumeric will return true if all of non-empty values in `records` can
+// be parsed as number.
+//
+func isAllNumeric(records tabula.Records) bool {
+	for _, rec := range records {
+		if rec == nil {
+			continue
+		}
+
+		v := rec.String()
+		if v == "" {
+			continue
+		}
+
+		_, e := strconv.ParseFloat(v, 64)
+		if e != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/dataset"
+	"github.com/shuLhan/tabula"
+	"testing"
+)
+
+func TestValidateTypes(t *testing.T) {
+	warns := dataset.ValidateTypes(readIris(t))
+	if len(warns) != 0 {
+		t.Fatalf("Expecting no warning, got %v", warns)
+	}
+
+	ds := &tabula.Claset{}
+	_, e := dsv.SimpleRead("../testdata/iris/iris_mislabel.dsv", ds)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	warns = dataset.ValidateTypes(ds)
+
+	fmt.Println("[dataset_test] warnings:", warns)
+
+	if len(warns) != 1 {
+		t.Fatalf("Expecting one warning, got %v", warns)
+	}
+	if warns[0].ColumnIndex != 0 {
+		t.Fatalf("Expecting warning on column 0, got %d",
+			warns[0].ColumnIndex)
+	}
+	if warns[0].Msg != dataset.WarnNumericString {
+		t.Fatalf("Expecting warning %q, got %q",
+			dataset.WarnNumericString, warns[0].Msg)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//
+// Package featureselect implement methods for selecting subset of features
+// in dataset.
+//
+package featureselect
+
+import (
+	"errors"
+	"fmt"
+	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/go-mining/classifier/rf"
+	"github.com/shuLhan/tabula"
+	"os"
+	"strconv"
+)
+
+const (
+	// DefNTree default number of tree in forest that is build on each
+	// iteration of RFE.
+	DefNTree = 50
+)
+
+var (
+	// DEBUG debug level, set from environment.
+	DEBUG = 0
+)
+
+var (
+	// ErrInvalidKeep will tell you when number of features to keep is
+	// less or equal to zero.
+	ErrInvalidKeep = errors.New("featureselect: number of features to" +
+		" keep must be greater than zero")
+)
+
+func init() {
+	var e error
+	DEBUG, e = strconv.Atoi(os.Getenv("FEATURESELECT_DEBUG"))
+	if e != nil {
+		DEBUG = 0
+	}
+}
+
+/*
+RFE will select `keep` features in `samples` using recursive feature
+elimination, and return the index of selected columns, in ascending order.
+
+Algorithm,
+
+(1) Start with all features, excluding the class.
+(2) While number of selected features is greater than `keep`,
+(2.1) create new samples with only the selected features and class,
+(2.2) build random forest using the new samples,
+(2.3) remove the feature with the lowest Gini importance.
+
+If `keep` is greater or equal to number of features, all features will be
+returned.
+*/
+func RFE(samples tabula.ClasetInterface, keep int) (selected []int, e error) {
+	if keep <= 0 {
+		return nil, ErrInvalidKeep
+	}
+
+	// (1)
+	classIdx := samples.GetClassIndex()
+	for x := 0; x < samples.GetNColumn(); x++ {
+		if x != classIdx {
+			selected = append(selected, x)
+		}
+	}
+
+	// (2)
+	for len(selected) > keep {
+		// (2.1)
+		subset := SelectColumns(samples, selected)
+
+		// (2.2)
+		forest := rf.Runtime{
+			Runtime: classifier.Runtime{
+				OOBStatsFile: os.DevNull,
+			},
+			NTree: DefNTree,
+		}
+
+		e = forest.Build(subset)
+		if e != nil {
+			return nil, e
+		}
+
+		// (2.3)
+		importances := forest.FeatureImportance()
+
+		minIdx := 0
+		for x := 1; x < len(selected); x++ {
+			if importances[x] < importances[minIdx] {
+				minIdx = x
+			}
+		}
+
+		if DEBUG >= 1 {
+			fmt.Printf("[featureselect] importances: %v, remove"+
+				" column %d\n", importances, selected[minIdx])
+		}
+
+		selected = append(selected[:minIdx], selected[minIdx+1:]...)
+	}
+
+	return selected, nil
+}
+
+//
+// SelectColumns will create and return new samples that contain only
+// columns in `cols` and class column, where the class is the last column.
+// Each record is cloned, so modifying the new samples will not change the
+// original.
+//
+func SelectColumns(samples tabula.ClasetInterface, cols []int) (
+	subset tabula.ClasetInterface,
+) {
+	classIdx := samples.GetClassIndex()
+	colIdx := append(append([]int{}, cols...), classIdx)
+
+	types := make([]int, 0, len(colIdx))
+	names := make([]string, 0, len(colIdx))
+	for _, idx := range colIdx {
+		col := samples.GetColumn(idx)
+		types = append(types, col.GetType())
+		names = append(names, col.GetName())
+	}
+
+	subset = tabula.NewClaset(tabula.DatasetModeMatrix, types, names)
+
+	subsetCols := subset.GetColumns()
+	for x, idx := range colIdx {
+		(*subsetCols)[x].ValueSpace = samples.GetColumn(idx).ValueSpace
+	}
+
+	subset.SetClassIndex(len(cols))
+
+	for _, row := range *samples.GetRows() {
+		newRow := make(tabula.Row, 0, len(colIdx))
+		for _, idx := range colIdx {
+			newRow = append(newRow, (*row)[idx].Clone())
+		}
+		subset.PushRow(&newRow)
+	}
+
+	subset.RecountMajorMinor()
+
+	return subset
+}
@@ -0,0 +1,76 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package featureselect_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/go-mining/classifier/rf"
+	"github.com/shuLhan/go-mining/dataset"
+	"github.com/shuLhan/go-mining/featureselect"
+	"github.com/shuLhan/tabula"
+	"os"
+	"testing"
+)
+
+func TestRFE(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../testdata/forensic_glass/fgl.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	_, e = featureselect.RFE(&samples, 0)
+	if e != featureselect.ErrInvalidKeep {
+		t.Fatalf("Expecting error %v, got %v",
+			featureselect.ErrInvalidKeep, e)
+	}
+
+	train, test := dataset.TrainTestSplit(&samples, 0.7, 1, true)
+
+	selected, e := featureselect.RFE(train, 3)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	fmt.Println("[featureselect_test] selected:", selected)
+
+	if len(selected) != 3 {
+		t.Fatalf("Expecting 3 selected features, got %v", selected)
+	}
+	for _, idx := range selected {
+		if idx == samples.GetClassIndex() {
+			t.Fatalf("Expecting class is not selected, got %v",
+				selected)
+		}
+	}
+
+	trainset := featureselect.SelectColumns(train, selected)
+	testset := featureselect.SelectColumns(test, selected)
+
+	forest := rf.Runtime{
+		Runtime: classifier.Runtime{
+			OOBStatsFile: os.DevNull,
+		},
+		NTree: 100,
+	}
+
+	e = forest.Build(trainset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	predicts, _ := forest.PredictSet(testset)
+	accuracy := classifier.AccuracyOf(testset.GetClassAsStrings(),
+		predicts)
+
+	fmt.Println("[featureselect_test] accuracy:", accuracy)
+
+	if accuracy < 0.5 {
+		t.Fatalf("Expecting accuracy with 3 features at least 0.5,"+
+			" got %f", accuracy)
+	}
+}
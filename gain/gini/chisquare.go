@@ -0,0 +1,168 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gini
+
+import (
+	"fmt"
+	"github.com/shuLhan/numerus"
+	"github.com/shuLhan/tekstus"
+)
+
+//
+// ComputeDiscreteChiSquare is an alternative to ComputeDiscrete where each
+// partition of discrete values is evaluated by the chi-squared statistic
+// between the partition and the class distribution, instead of Gini gain.
+//
+// The statistic of each partition is saved in Gain, and the partition with
+// the largest statistic in MaxPartGain and MaxGainValue. Index and Value are
+// not used.
+//
+func (gini *Gini) ComputeDiscreteChiSquare(A *[]string, discval *[]string,
+	T *[]string, C *[]string,
+) {
+	gini.IsContinu = false
+
+	gini.createDiscretePartition((*discval))
+
+	if DEBUG >= 2 {
+		fmt.Println("[gini] part :", gini.DiscretePart)
+	}
+
+	gini.Index = make([]float64, len(gini.DiscretePart))
+	gini.Gain = make([]float64, len(gini.DiscretePart))
+
+	for i, subPart := range gini.DiscretePart {
+		if len(subPart) <= 0 {
+			continue
+		}
+
+		var subTs [][]string
+
+		for _, part := range subPart {
+			var subT []string
+
+			for _, el := range part {
+				for t, a := range *A {
+					if a == el {
+						subT = append(subT, (*T)[t])
+					}
+				}
+			}
+
+			subTs = append(subTs, subT)
+		}
+
+		gini.Gain[i] = chiSquare(subTs, C)
+
+		if DEBUG >= 3 {
+			fmt.Printf("[gini] ChiSquare(a=%s) = %f\n", subPart,
+				gini.Gain[i])
+		}
+
+		if gini.MaxGainValue < gini.Gain[i] {
+			gini.MaxGainValue = gini.Gain[i]
+			gini.MaxPartGain = i
+		}
+	}
+}
+
+//
+// ComputeContinuChiSquare is an alternative to ComputeContinu where each
+// partition of continuous attribute is evaluated by the chi-squared
+// statistic between the left and right samples and the class distribution.
+//
+func (gini *Gini) ComputeContinuChiSquare(A *[]float64, T *[]string,
+	C *[]string,
+) {
+	gini.IsContinu = true
+
+	A2 := make([]float64, len(*A))
+	copy(A2, *A)
+
+	T2 := make([]string, len(*T))
+	copy(T2, *T)
+
+	gini.SortedIndex = numerus.Floats64IndirectSort(A2, true)
+
+	tekstus.StringsSortByIndex(&T2, gini.SortedIndex)
+
+	gini.createContinuPartition(&A2)
+
+	gini.Index = make([]float64, len(gini.ContinuPart))
+	gini.Gain = make([]float64, len(gini.ContinuPart))
+
+	nsample := len(A2)
+
+	for p, contVal := range gini.ContinuPart {
+		partidx := nsample
+		for x, attrVal := range A2 {
+			if attrVal > contVal {
+				partidx = x
+				break
+			}
+		}
+
+		subTs := [][]string{T2[0:partidx], T2[partidx:]}
+
+		gini.Gain[p] = chiSquare(subTs, C)
+
+		if DEBUG >= 3 {
+			fmt.Printf("[gini] ChiSquare(%v) = %f\n", contVal,
+				gini.Gain[p])
+		}
+
+		if gini.MaxGainValue < gini.Gain[p] {
+			gini.MaxGainValue = gini.Gain[p]
+			gini.MaxPartGain = p
+		}
+	}
+}
+
+//
+// chiSquare compute the chi-squared statistic of contingency table between
+// each subset of target in `subTs` and the classes `C`, using formula,
+//
+//	sum ((observed - expected)^2 / expected)
+//
+// where expected count of class in subset is,
+//
+//	number-of-samples-in-subset * number-of-class-samples / number-of-samples
+//
+func chiSquare(subTs [][]string, C *[]string) (v float64) {
+	observed := make([][]int, len(subTs))
+	subCounts := make([]int, len(subTs))
+	classCounts := make([]int, len(*C))
+	n := 0
+
+	for x, subT := range subTs {
+		observed[x] = tekstus.WordsCountTokens(subT, *C, true)
+
+		for y, count := range observed[x] {
+			subCounts[x] += count
+			classCounts[y] += count
+			n += count
+		}
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	for x := range subTs {
+		nsub := float64(subCounts[x])
+
+		for y, count := range observed[x] {
+			expected := nsub * float64(classCounts[y]) / float64(n)
+			if expected == 0 {
+				continue
+			}
+
+			diff := float64(count) - expected
+			v += (diff * diff) / expected
+		}
+	}
+
+	return v
+}
@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"github.com/shuLhan/numerus"
 	"github.com/shuLhan/tekstus"
+	"math/rand"
 	"os"
 	"strconv"
 )
@@ -57,6 +58,13 @@ type Gini struct {
 	Index []float64
 	// Gain contain information gain for each partition.
 	Gain []float64
+	// MaxContinuPart if its greater than zero, limit the number of
+	// partition in continuous attribute, where only partition value near
+	// the quantiles of samples is evaluated.
+	MaxContinuPart int
+	// impurity if its set, will be used to compute impurity instead of
+	// Gini index.
+	impurity ImpurityFunc
 }
 
 func init() {
@@ -229,6 +237,86 @@ func (gini *Gini) ComputeContinu(A *[]float64, T *[]string, C *[]string) {
 	gini.computeContinuGain(&A2, &T2, C)
 }
 
+/*
+ComputeContinuSorted is like ComputeContinu but use `sortedIdx`, the index of
+attribute A sorted in ascending order, instead of sorting the attribute
+again. This can be used when the sorted index is known from the previous
+computation (e.g. from the parent node in tree).
+*/
+func (gini *Gini) ComputeContinuSorted(A *[]float64, T *[]string, C *[]string,
+	sortedIdx []int,
+) {
+	gini.IsContinu = true
+
+	A2 := make([]float64, len(sortedIdx))
+	T2 := make([]string, len(sortedIdx))
+
+	for x, idx := range sortedIdx {
+		A2[x] = (*A)[idx]
+		T2[x] = (*T)[idx]
+	}
+
+	gini.SortedIndex = make([]int, len(sortedIdx))
+	copy(gini.SortedIndex, sortedIdx)
+
+	if DEBUG >= 1 {
+		fmt.Println("[gini] attr sorted :", A2)
+	}
+
+	gini.createContinuPartition(&A2)
+
+	gini.Index = make([]float64, len(gini.ContinuPart))
+	gini.Gain = make([]float64, len(gini.ContinuPart))
+	gini.MinIndexValue = 1.0
+
+	gini.Value = gini.compute(&T2, C)
+
+	gini.computeContinuGain(&A2, &T2, C)
+}
+
+/*
+ComputeContinuRandom is like ComputeContinu but only compute the Gini gain on
+one random partition value, drawn uniformly between the minimum and maximum
+value of attribute, instead of all midpoints. This is used to build
+extremely randomized trees (Extra-Trees).
+If all values in attribute is equal, no partition is created.
+*/
+func (gini *Gini) ComputeContinuRandom(A *[]float64, T *[]string, C *[]string) {
+	gini.IsContinu = true
+
+	A2 := make([]float64, len(*A))
+	copy(A2, *A)
+
+	T2 := make([]string, len(*T))
+	copy(T2, *T)
+
+	gini.SortedIndex = numerus.Floats64IndirectSort(A2, true)
+
+	tekstus.StringsSortByIndex(&T2, gini.SortedIndex)
+
+	gini.ContinuPart = make([]float64, 0, 1)
+	if len(A2) > 0 {
+		min := A2[0]
+		max := A2[len(A2)-1]
+		if min < max {
+			part := min + rand.Float64()*(max-min)
+			gini.ContinuPart = append(gini.ContinuPart, part)
+		}
+	}
+
+	if DEBUG >= 1 {
+		fmt.Println("[gini] random partition:", gini.ContinuPart)
+	}
+
+	gini.Index = make([]float64, len(gini.ContinuPart))
+	gini.Gain = make([]float64, len(gini.ContinuPart))
+	gini.MinIndexValue = 1.0
+
+	gini.Value = gini.compute(&T2, C)
+
+	gini.computeContinuGain(&A2, &T2, C)
+}
+
 /*
 createContinuPartition for dividing class and computing Gini index.
 
@@ -238,6 +326,9 @@ func (gini *Gini) createContinuPartition(A *[]float64) {
 	l := len(*A)
 	gini.ContinuPart = make([]float64, 0)
 
+	// nleft contain number of samples in the left of each partition.
+	var nleft []int
+
 	// loop from first index until last index - 1
 	for i := 0; i < l-1; i++ {
 		sum := (*A)[i] + (*A)[i+1]
@@ -262,8 +353,43 @@ func (gini *Gini) createContinuPartition(A *[]float64) {
 		}
 		if !exist {
 			gini.ContinuPart = append(gini.ContinuPart, med)
+			nleft = append(nleft, i+1)
 		}
 	}
+
+	if gini.MaxContinuPart > 0 &&
+		len(gini.ContinuPart) > gini.MaxContinuPart {
+		gini.selectQuantilePartition(l, nleft)
+	}
+}
+
+/*
+selectQuantilePartition will reduce the continuous partition into, at most,
+MaxContinuPart values, where each value is the first partition that have
+number of samples in the left greater or equal to the quantile of `nsample`.
+*/
+func (gini *Gini) selectQuantilePartition(nsample int, nleft []int) {
+	nquant := gini.MaxContinuPart + 1
+	parts := make([]float64, 0, gini.MaxContinuPart)
+
+	p := 0
+	for q := 1; q < nquant; q++ {
+		k := (q * nsample) / nquant
+
+		for p < len(nleft) && nleft[p] < k {
+			p++
+		}
+		if p >= len(nleft) {
+			break
+		}
+
+		v := gini.ContinuPart[p]
+		if len(parts) == 0 || parts[len(parts)-1] != v {
+			parts = append(parts, v)
+		}
+	}
+
+	gini.ContinuPart = parts
 }
 
 /*
@@ -279,6 +405,10 @@ func (gini *Gini) compute(T *[]string, C *[]string) float64 {
 		return 0
 	}
 
+	if gini.impurity != nil {
+		return gini.computeImpurity(T, C)
+	}
+
 	classCount := tekstus.WordsCountTokens(*T, *C, true)
 
 	var sump2 float64
@@ -387,6 +517,15 @@ func (gini *Gini) GetMaxPartGainValue() interface{} {
 	return gini.DiscretePart[gini.MaxPartGain]
 }
 
+/*
+GetMaxPartValue is an alias of GetMaxPartGainValue. It return the partition
+that have the maximum Gini gain, as float64 for continuous attribute or as
+tekstus.ListStrings for discrete attribute.
+*/
+func (gini *Gini) GetMaxPartValue() interface{} {
+	return gini.GetMaxPartGainValue()
+}
+
 /*
 GetMaxGainValue return the value of partition which contain the maximum Gini
 gain.
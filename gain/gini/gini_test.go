@@ -6,9 +6,12 @@ package gini_test
 
 import (
 	"fmt"
+	"math"
+	"reflect"
 	"testing"
 
 	"github.com/shuLhan/go-mining/gain/gini"
+	"github.com/shuLhan/tekstus"
 )
 
 var data = [][]float64{
@@ -59,3 +62,133 @@ func TestComputeDiscrete(t *testing.T) {
 		fmt.Println(gini)
 	}
 }
+
+func TestComputeContinuWeighted(t *testing.T) {
+	attr := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	target := []string{"N", "N", "N", "N", "N", "P", "N", "P", "P"}
+	weights := []float64{1, 1, 1, 1, 1, 1, 1, 1, 1}
+
+	GINI := gini.Gini{}
+	GINI.ComputeContinuWeighted(&attr, &target, &classes, &weights)
+
+	fmt.Println(">>> gini uniform weights:", GINI)
+
+	got := GINI.GetMaxPartGainValue().(float64)
+	if got != 5.5 {
+		t.Fatal("Expecting split at 5.5, got", got)
+	}
+
+	// Upweighting the negative sample between the positive samples
+	// should move the split to the right.
+	weights[6] = 5
+
+	GINI = gini.Gini{}
+	GINI.ComputeContinuWeighted(&attr, &target, &classes, &weights)
+
+	fmt.Println(">>> gini upweighted:", GINI)
+
+	got = GINI.GetMaxPartGainValue().(float64)
+	if got != 7.5 {
+		t.Fatal("Expecting split at 7.5, got", got)
+	}
+}
+
+func TestComputeWeightedZero(t *testing.T) {
+	attr := []float64{1, 2, 3, 4}
+	target := []string{"N", "N", "P", "P"}
+	weights := []float64{0, 0, 0, 0}
+
+	GINI := gini.Gini{}
+	GINI.ComputeContinuWeighted(&attr, &target, &classes, &weights)
+
+	for p, gain := range GINI.Gain {
+		if math.IsNaN(gain) {
+			t.Fatalf("Expecting gain of partition %d is not NaN", p)
+		}
+	}
+
+	discAttr := []string{"A", "B", "A", "B"}
+	discval := []string{"A", "B"}
+
+	GINI = gini.Gini{}
+	GINI.ComputeDiscreteWeighted(&discAttr, &discval, &target, &classes,
+		&weights)
+
+	for p, gain := range GINI.Gain {
+		if math.IsNaN(gain) {
+			t.Fatalf("Expecting gain of partition %d is not NaN", p)
+		}
+	}
+}
+
+func TestGetMaxPartValue(t *testing.T) {
+	// Continuous attribute.
+	attr := []float64{1, 2, 3, 4}
+	target := []string{"N", "N", "P", "P"}
+
+	GINI := gini.Gini{}
+	GINI.ComputeContinu(&attr, &target, &classes)
+
+	contv, ok := GINI.GetMaxPartValue().(float64)
+	if !ok {
+		t.Fatal("Expecting float64 partition on continuous attribute")
+	}
+	if contv != 2.5 {
+		t.Fatal("Expecting split at 2.5, got", contv)
+	}
+	if contv != GINI.GetMaxPartGainValue().(float64) {
+		t.Fatal("Expecting the same value as GetMaxPartGainValue")
+	}
+
+	// Discrete attribute.
+	discAttr := []string{"T", "T", "F", "F"}
+
+	GINI = gini.Gini{}
+	GINI.ComputeDiscrete(&discAttr, &discreteValues, &target, &classes)
+
+	discv, ok := GINI.GetMaxPartValue().(tekstus.ListStrings)
+	if !ok {
+		t.Fatal("Expecting tekstus.ListStrings partition on discrete" +
+			" attribute")
+	}
+
+	exp := GINI.GetMaxPartGainValue().(tekstus.ListStrings)
+	if !reflect.DeepEqual(exp, discv) {
+		t.Fatalf("Expecting %v, got %v", exp, discv)
+	}
+	if len(discv) != 2 {
+		t.Fatal("Expecting two subsets, got", discv)
+	}
+}
+
+func TestMaxContinuPart(t *testing.T) {
+	n := 100
+	attr := make([]float64, n)
+	target := make([]string, n)
+	for x := range attr {
+		attr[x] = float64(x + 1)
+		if x < 70 {
+			target[x] = "N"
+		} else {
+			target[x] = "P"
+		}
+	}
+
+	GINI := gini.Gini{
+		MaxContinuPart: 9,
+	}
+	GINI.ComputeContinu(&attr, &target, &classes)
+
+	fmt.Println(">>> gini partitions:", GINI.ContinuPart)
+
+	if len(GINI.ContinuPart) > 9 {
+		t.Fatal("Expecting at most 9 partitions, got",
+			len(GINI.ContinuPart))
+	}
+
+	// The split at 70.5 is on the decile, so it should be selected.
+	got := GINI.GetMaxPartGainValue().(float64)
+	if got != 70.5 {
+		t.Fatal("Expecting split at 70.5, got", got)
+	}
+}
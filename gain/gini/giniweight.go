@@ -0,0 +1,239 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gini
+
+import (
+	"fmt"
+	"github.com/shuLhan/numerus"
+	"github.com/shuLhan/tekstus"
+)
+
+//
+// ComputeContinuWeighted is an alternative to ComputeContinu where each
+// sample in target attribute T has weight in W. The class probabilities is
+// computed using sum of weights instead of number of samples.
+//
+// Algorithm,
+// (0) Make a copy of attribute, target, and weights.
+// (1) Sort the attribute.
+// (2) Sort the target and weights using sorted index.
+// (3) Create continu partition.
+// (4) Create temporary space for gini index and gini gain.
+// (5) Compute gini index for all target.
+// (6) Compute gain for each partition.
+//
+func (gini *Gini) ComputeContinuWeighted(A *[]float64, T *[]string,
+	C *[]string, W *[]float64,
+) {
+	gini.IsContinu = true
+
+	// (0)
+	A2 := make([]float64, len(*A))
+	copy(A2, *A)
+
+	T2 := make([]string, len(*T))
+	copy(T2, *T)
+
+	W2 := make([]float64, len(*W))
+	copy(W2, *W)
+
+	// (1)
+	gini.SortedIndex = numerus.Floats64IndirectSort(A2, true)
+
+	// (2)
+	tekstus.StringsSortByIndex(&T2, gini.SortedIndex)
+	numerus.Floats64SortByIndex(&W2, gini.SortedIndex)
+
+	// (3)
+	gini.createContinuPartition(&A2)
+
+	// (4)
+	gini.Index = make([]float64, len(gini.ContinuPart))
+	gini.Gain = make([]float64, len(gini.ContinuPart))
+	gini.MinIndexValue = 1.0
+
+	// (5)
+	gini.Value = gini.computeWeighted(T2, W2, C)
+
+	// (6)
+	gini.computeContinuGainWeighted(&A2, &T2, C, &W2)
+}
+
+//
+// ComputeDiscreteWeighted is an alternative to ComputeDiscrete where each
+// sample in target attribute T has weight in W.
+//
+func (gini *Gini) ComputeDiscreteWeighted(A *[]string, discval *[]string,
+	T *[]string, C *[]string, W *[]float64,
+) {
+	gini.IsContinu = false
+
+	gini.createDiscretePartition((*discval))
+
+	if DEBUG >= 2 {
+		fmt.Println("[gini] part :", gini.DiscretePart)
+	}
+
+	gini.Index = make([]float64, len(gini.DiscretePart))
+	gini.Gain = make([]float64, len(gini.DiscretePart))
+	gini.MinIndexValue = 1.0
+
+	gini.Value = gini.computeWeighted(*T, *W, C)
+
+	gini.computeDiscreteGainWeighted(A, T, C, W)
+}
+
+//
+// computeWeighted will compute Gini value for target T with weight W using
+// formula,
+//
+//	1 - sum (probability of each classes in T)
+//
+// where the probability of class is sum of weights in class divided by sum
+// of all weights.
+//
+func (gini *Gini) computeWeighted(T []string, W []float64, C *[]string) (
+	v float64,
+) {
+	sumw := numerus.Floats64Sum(W)
+	if sumw == 0 {
+		return 0
+	}
+
+	classWeights := make([]float64, len(*C))
+
+	for x, t := range T {
+		for y, c := range *C {
+			if t == c {
+				classWeights[y] += W[x]
+				break
+			}
+		}
+	}
+
+	var sump2 float64
+
+	for x, cw := range classWeights {
+		p := cw / sumw
+		sump2 += (p * p)
+
+		if DEBUG >= 3 {
+			fmt.Printf("[gini] compute weighted (%s): (%f/%f)^2 = %f\n",
+				(*C)[x], cw, sumw, p*p)
+		}
+	}
+
+	return 1 - sump2
+}
+
+//
+// computeContinuGainWeighted will compute gain for each partition using the
+// sum of weights of samples in left and right partition.
+// If sum of all weights is zero, the gain of all partitions is zero.
+//
+func (gini *Gini) computeContinuGainWeighted(A *[]float64, T *[]string,
+	C *[]string, W *[]float64,
+) {
+	var gleft, gright float64
+
+	nsample := len(*A)
+	sumw := numerus.Floats64Sum(*W)
+	if sumw == 0 {
+		return
+	}
+
+	for p, contVal := range gini.ContinuPart {
+		partidx := nsample
+		for x, attrVal := range *A {
+			if attrVal > contVal {
+				partidx = x
+				break
+			}
+		}
+
+		wleft := (*W)[0:partidx]
+		wright := (*W)[partidx:]
+
+		pleft := numerus.Floats64Sum(wleft) / sumw
+		pright := numerus.Floats64Sum(wright) / sumw
+
+		gleft = gini.computeWeighted((*T)[0:partidx], wleft, C)
+		gright = gini.computeWeighted((*T)[partidx:], wright, C)
+
+		gini.Index[p] = ((pleft * gleft) + (pright * gright))
+		gini.Gain[p] = gini.Value - gini.Index[p]
+
+		if DEBUG >= 3 {
+			fmt.Printf("[gini] GiniGainWeighted(%v) = %f - (%f * %f) + (%f * %f) = %f\n",
+				contVal, gini.Value, pleft, gleft,
+				pright, gright, gini.Gain[p])
+		}
+
+		if gini.MinIndexValue > gini.Index[p] && gini.Index[p] != 0 {
+			gini.MinIndexValue = gini.Index[p]
+			gini.MinIndexPart = p
+		}
+
+		if gini.MaxGainValue < gini.Gain[p] {
+			gini.MaxGainValue = gini.Gain[p]
+			gini.MaxPartGain = p
+		}
+	}
+}
+
+//
+// computeDiscreteGainWeighted will compute Gini index and Gain for each
+// partition using the sum of weights of samples in each subset.
+// If sum of all weights is zero, the gain of all partitions is zero.
+//
+func (gini *Gini) computeDiscreteGainWeighted(A *[]string, T *[]string,
+	C *[]string, W *[]float64,
+) {
+	sumw := numerus.Floats64Sum(*W)
+	if sumw == 0 {
+		return
+	}
+
+	for i, subPart := range gini.DiscretePart {
+		if len(subPart) <= 0 {
+			continue
+		}
+
+		sumGI := 0.0
+		for _, part := range subPart {
+			var subT []string
+			var subW []float64
+
+			for _, el := range part {
+				for t, a := range *A {
+					if a != el {
+						continue
+					}
+					subT = append(subT, (*T)[t])
+					subW = append(subW, (*W)[t])
+				}
+			}
+
+			giniIndex := gini.computeWeighted(subT, subW, C)
+
+			p := numerus.Floats64Sum(subW) / sumw
+
+			sumGI += p * giniIndex
+		}
+
+		gini.Index[i] = sumGI
+		gini.Gain[i] = gini.Value - sumGI
+
+		if gini.MinIndexValue > gini.Index[i] && gini.Index[i] != 0 {
+			gini.MinIndexValue = gini.Index[i]
+			gini.MinIndexPart = i
+		}
+
+		if gini.MaxGainValue < gini.Gain[i] {
+			gini.MaxGainValue = gini.Gain[i]
+			gini.MaxPartGain = i
+		}
+	}
+}
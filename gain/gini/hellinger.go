@@ -0,0 +1,169 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gini
+
+import (
+	"fmt"
+	"github.com/shuLhan/numerus"
+	"github.com/shuLhan/tekstus"
+	"math"
+)
+
+//
+// ComputeDiscreteHellinger is an alternative to ComputeDiscrete where each
+// partition of discrete values is evaluated by the Hellinger distance
+// between the distribution of positive class (the first class in `C`) and
+// the distribution of the rest of classes on the partition, instead of Gini
+// gain. Hellinger distance is not sensitive to the class imbalance.
+//
+// The distance of each partition is saved in Gain, and the partition with
+// the largest distance in MaxPartGain and MaxGainValue. Index and Value are
+// not used.
+//
+func (gini *Gini) ComputeDiscreteHellinger(A *[]string, discval *[]string,
+	T *[]string, C *[]string,
+) {
+	gini.IsContinu = false
+
+	gini.createDiscretePartition((*discval))
+
+	if DEBUG >= 2 {
+		fmt.Println("[gini] part :", gini.DiscretePart)
+	}
+
+	gini.Index = make([]float64, len(gini.DiscretePart))
+	gini.Gain = make([]float64, len(gini.DiscretePart))
+
+	for i, subPart := range gini.DiscretePart {
+		if len(subPart) <= 0 {
+			continue
+		}
+
+		var subTs [][]string
+
+		for _, part := range subPart {
+			var subT []string
+
+			for _, el := range part {
+				for t, a := range *A {
+					if a == el {
+						subT = append(subT, (*T)[t])
+					}
+				}
+			}
+
+			subTs = append(subTs, subT)
+		}
+
+		gini.Gain[i] = hellinger(subTs, C)
+
+		if DEBUG >= 3 {
+			fmt.Printf("[gini] Hellinger(a=%s) = %f\n", subPart,
+				gini.Gain[i])
+		}
+
+		if gini.MaxGainValue < gini.Gain[i] {
+			gini.MaxGainValue = gini.Gain[i]
+			gini.MaxPartGain = i
+		}
+	}
+}
+
+//
+// ComputeContinuHellinger is an alternative to ComputeContinu where each
+// partition of continuous attribute is evaluated by the Hellinger distance
+// between the distribution of positive class and the rest of classes on the
+// left and right samples.
+//
+func (gini *Gini) ComputeContinuHellinger(A *[]float64, T *[]string,
+	C *[]string,
+) {
+	gini.IsContinu = true
+
+	A2 := make([]float64, len(*A))
+	copy(A2, *A)
+
+	T2 := make([]string, len(*T))
+	copy(T2, *T)
+
+	gini.SortedIndex = numerus.Floats64IndirectSort(A2, true)
+
+	tekstus.StringsSortByIndex(&T2, gini.SortedIndex)
+
+	gini.createContinuPartition(&A2)
+
+	gini.Index = make([]float64, len(gini.ContinuPart))
+	gini.Gain = make([]float64, len(gini.ContinuPart))
+
+	nsample := len(A2)
+
+	for p, contVal := range gini.ContinuPart {
+		partidx := nsample
+		for x, attrVal := range A2 {
+			if attrVal > contVal {
+				partidx = x
+				break
+			}
+		}
+
+		subTs := [][]string{T2[0:partidx], T2[partidx:]}
+
+		gini.Gain[p] = hellinger(subTs, C)
+
+		if DEBUG >= 3 {
+			fmt.Printf("[gini] Hellinger(%v) = %f\n", contVal,
+				gini.Gain[p])
+		}
+
+		if gini.MaxGainValue < gini.Gain[p] {
+			gini.MaxGainValue = gini.Gain[p]
+			gini.MaxPartGain = p
+		}
+	}
+}
+
+//
+// hellinger compute the Hellinger distance between the distribution of
+// positive class, the first class in `C`, and the distribution of the rest
+// of classes on each subset of target in `subTs`, using formula,
+//
+//	sqrt (sum ((sqrt(pos(subset)/pos) - sqrt(neg(subset)/neg))^2))
+//
+// where pos and neg is number of positive and negative samples.
+//
+func hellinger(subTs [][]string, C *[]string) (v float64) {
+	if len(*C) <= 0 {
+		return 0
+	}
+
+	positive := (*C)[0]
+	npos := make([]int, len(subTs))
+	nneg := make([]int, len(subTs))
+	var totalPos, totalNeg int
+
+	for x, subT := range subTs {
+		for _, t := range subT {
+			if t == positive {
+				npos[x]++
+			} else {
+				nneg[x]++
+			}
+		}
+		totalPos += npos[x]
+		totalNeg += nneg[x]
+	}
+
+	if totalPos == 0 || totalNeg == 0 {
+		return 0
+	}
+
+	for x := range subTs {
+		diff := math.Sqrt(float64(npos[x])/float64(totalPos)) -
+			math.Sqrt(float64(nneg[x])/float64(totalNeg))
+		v += diff * diff
+	}
+
+	return math.Sqrt(v)
+}
@@ -0,0 +1,57 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gini
+
+import (
+	"github.com/shuLhan/tekstus"
+)
+
+//
+// ImpurityFunc is a function to compute impurity of samples, where
+// `classCounts` contain number of samples for each class value (including
+// class with zero sample) and `total` is number of samples.
+//
+type ImpurityFunc func(classCounts map[string]int, total int) float64
+
+//
+// ComputeDiscreteImpurity is like ComputeDiscrete but use impurity function
+// `fn` instead of Gini index. The gain of each partition is computed as,
+//
+//	fn(T) - sum (count(Ti)/count(T) * fn(Ti))
+//
+func (gini *Gini) ComputeDiscreteImpurity(A *[]string, discval *[]string,
+	T *[]string, C *[]string, fn ImpurityFunc,
+) {
+	gini.impurity = fn
+	gini.ComputeDiscrete(A, discval, T, C)
+	gini.impurity = nil
+}
+
+//
+// ComputeContinuImpurity is like ComputeContinu but use impurity function
+// `fn` instead of Gini index.
+//
+func (gini *Gini) ComputeContinuImpurity(A *[]float64, T *[]string,
+	C *[]string, fn ImpurityFunc,
+) {
+	gini.impurity = fn
+	gini.ComputeContinu(A, T, C)
+	gini.impurity = nil
+}
+
+//
+// computeImpurity will count each class `C` in target `T` and pass it to
+// impurity function.
+//
+func (gini *Gini) computeImpurity(T *[]string, C *[]string) float64 {
+	counts := tekstus.WordsCountTokens(*T, *C, true)
+
+	classCounts := make(map[string]int, len(*C))
+	for x, class := range *C {
+		classCounts[class] = counts[x]
+	}
+
+	return gini.impurity(classCounts, len(*T))
+}
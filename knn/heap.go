@@ -0,0 +1,121 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package knn
+
+import (
+	"container/heap"
+	"github.com/shuLhan/tabula"
+)
+
+//
+// maxHeap is neighbors where the farthest neighbor is at the top.
+// This type implement the heap interface.
+//
+type maxHeap struct {
+	Neighbors
+	// ids contain the index of each neighbor in samples, used to order
+	// neighbors with equal distance.
+	ids []int
+}
+
+//
+// Less return true if distance at i is greater than j, so the farthest
+// neighbor is at the top of heap. If distances is equal, neighbor with
+// greater index in samples is considered farther.
+//
+func (h *maxHeap) Less(i, j int) bool {
+	if h.distances[i] != h.distances[j] {
+		return h.distances[i] > h.distances[j]
+	}
+	return h.ids[i] > h.ids[j]
+}
+
+//
+// Swap content of neighbor in index i with index j, including their index in
+// samples.
+//
+func (h *maxHeap) Swap(i, j int) {
+	h.Neighbors.Swap(i, j)
+	h.ids[i], h.ids[j] = h.ids[j], h.ids[i]
+}
+
+//
+// Push new neighbor into heap. This is for heap interface.
+//
+func (h *maxHeap) Push(x interface{}) {
+	n := x.(neighbor)
+	h.Add(n.row, n.distance)
+	h.ids = append(h.ids, n.id)
+}
+
+//
+// Pop the last neighbor from heap. This is for heap interface.
+//
+func (h *maxHeap) Pop() interface{} {
+	last := len(h.rows) - 1
+	n := neighbor{
+		row:      h.rows[last],
+		distance: h.distances[last],
+		id:       h.ids[last],
+	}
+
+	h.rows = h.rows[:last]
+	h.distances = h.distances[:last]
+	h.ids = h.ids[:last]
+
+	return n
+}
+
+//
+// pushBounded will push new neighbor, with index `id` in samples, into heap
+// while keeping only `k` nearest neighbors. If distance is equal with the
+// farthest neighbor, the one with lower index is kept.
+//
+func (h *maxHeap) pushBounded(row *tabula.Row, distance float64, id, k int) {
+	if h.Len() < k {
+		heap.Push(h, neighbor{row, distance, id})
+		return
+	}
+	if distance > h.distances[0] ||
+		(distance == h.distances[0] && id >= h.ids[0]) {
+		return
+	}
+
+	h.rows[0] = row
+	h.distances[0] = distance
+	h.ids[0] = id
+	heap.Fix(h, 0)
+}
+
+//
+// sorted will pop all neighbors in heap and return them sorted ascending by
+// distance, and by their index in samples if distances is equal.
+//
+func (h *maxHeap) sorted() (neighbors Neighbors) {
+	n := h.Len()
+	if n <= 0 {
+		return
+	}
+
+	neighbors.rows = make([]*tabula.Row, n)
+	neighbors.distances = make([]float64, n)
+
+	for x := n - 1; x >= 0; x-- {
+		last := heap.Pop(h).(neighbor)
+		neighbors.rows[x] = last.row
+		neighbors.distances[x] = last.distance
+	}
+
+	return neighbors
+}
+
+//
+// neighbor is a single row with their distance and index in samples.
+//
+type neighbor struct {
+	row      *tabula.Row
+	distance float64
+	id       int
+}
@@ -13,13 +13,26 @@ import (
 	"github.com/shuLhan/tabula"
 	"math"
 	"os"
+	"runtime"
 	"sort"
 	"strconv"
+	"sync"
 )
 
 const (
 	// TEuclidianDistance used in Runtime.DistanceMethod.
 	TEuclidianDistance = 0
+	// TEuclidianSquared used in Runtime.DistanceMethod, its equal to
+	// TEuclidianDistance without computing the square root.
+	// The order of neighbors is the same with TEuclidianDistance, but
+	// the distance value is not.
+	TEuclidianSquared = 1
+)
+
+const (
+	// minRowsPerWorker define the minimum number of samples computed by
+	// each goroutine. Samples less than this will be computed serially.
+	minRowsPerWorker = 512
 )
 
 var (
@@ -38,6 +51,19 @@ type Runtime struct {
 	ClassIndex int `json:"ClassIndex"`
 	// K define number of nearest neighbors that will be searched.
 	K int `json:"K"`
+	// KeepAllNeighbors if its true, FindNeighbors will compute and sort
+	// the distance to all samples and save it in AllNeighbors.
+	// Default is false, where only K nearest neighbors is kept using
+	// bounded heap and AllNeighbors will be empty.
+	KeepAllNeighbors bool `json:"KeepAllNeighbors"`
+	// FeatureWeights define the weight of each column when computing
+	// the distance, indexed by column index in dataset, where the
+	// squared difference of each column is multiplied by its weight.
+	// Column that does not have weight (index out of range) will have
+	// weight one.
+	// Set weight to zero to ignore the column. Negative weight is
+	// treated as zero.
+	FeatureWeights []float64 `json:"FeatureWeights"`
 
 	// AllNeighbors contain all neighbours
 	AllNeighbors Neighbors
@@ -53,69 +79,254 @@ func init() {
 
 /*
 ComputeEuclidianDistance compute the distance of instance with each sample in
-dataset `samples` and return it.
+dataset `samples` and save it in AllNeighbors, sorted by distance.
+Samples with equal distance is ordered by their index in `samples`.
+
+The samples is divided into several ranges, where distance in each range is
+computed concurrently, depends on number of samples and GOMAXPROCS.
 */
 func (in *Runtime) ComputeEuclidianDistance(samples *tabula.Rows,
 	instance *tabula.Row,
 ) {
-	for x := range *samples {
-		row := (*samples)[x]
-
-		// compute euclidian distance
-		d := 0.0
-		for y, rec := range *row {
-			if y == in.ClassIndex {
-				// skip class attribute
-				continue
-			}
+	nrow := len(*samples)
+
+	nworker := runtime.GOMAXPROCS(0)
+	if nworker > nrow/minRowsPerWorker {
+		nworker = nrow / minRowsPerWorker
+	}
+
+	if nworker <= 1 {
+		neighbors := in.computeEuclidianDistance(*samples, instance)
+		in.AllNeighbors.rows = append(in.AllNeighbors.rows,
+			neighbors.rows...)
+		in.AllNeighbors.distances = append(in.AllNeighbors.distances,
+			neighbors.distances...)
+
+		sort.Stable(&in.AllNeighbors)
+		return
+	}
 
-			ir := (*instance)[y]
-			diff := 0.0
+	size := (nrow + nworker - 1) / nworker
+	locals := make([]Neighbors, nworker)
 
-			diff = ir.Float() - rec.Float()
+	var wg sync.WaitGroup
 
-			d += math.Abs(diff)
+	for w := 0; w < nworker; w++ {
+		start := w * size
+		end := start + size
+		if end > nrow {
+			end = nrow
 		}
 
+		wg.Add(1)
+		go func(w int, rows tabula.Rows) {
+			defer wg.Done()
+			locals[w] = in.computeEuclidianDistance(rows, instance)
+		}(w, (*samples)[start:end])
+	}
+
+	wg.Wait()
+
+	// Merge local neighbors in the order of their range, so the result
+	// is equal to the serial computation.
+	for _, neighbors := range locals {
+		in.AllNeighbors.rows = append(in.AllNeighbors.rows,
+			neighbors.rows...)
+		in.AllNeighbors.distances = append(in.AllNeighbors.distances,
+			neighbors.distances...)
+	}
+
+	sort.Stable(&in.AllNeighbors)
+}
+
+//
+// computeEuclidianDistance compute the distance of instance with each row in
+// `rows` and return it as unsorted neighbors.
+//
+func (in *Runtime) computeEuclidianDistance(rows tabula.Rows,
+	instance *tabula.Row,
+) (
+	neighbors Neighbors,
+) {
+	for _, row := range rows {
+		d := in.euclidianDistance(row, instance)
+
 		// only add sample distance which is not zero (its probably
 		// we calculating with the instance itself)
 		if d != 0 {
-			in.AllNeighbors.Add(row, math.Sqrt(d))
+			neighbors.Add(row, d)
+		}
+	}
+	return
+}
+
+//
+// euclidianDistance return the distance between `row` and `instance`,
+// excluding the class attribute.
+// The squared difference of each column is multiplied by its weight in
+// FeatureWeights.
+// If DistanceMethod is TEuclidianSquared, the square root is not computed.
+//
+func (in *Runtime) euclidianDistance(row, instance *tabula.Row) float64 {
+	d := 0.0
+	for y, rec := range *row {
+		if y == in.ClassIndex {
+			// skip class attribute
+			continue
+		}
+
+		ir := (*instance)[y]
+		diff := 0.0
+
+		diff = ir.Float() - rec.Float()
+
+		w := 1.0
+		if y < len(in.FeatureWeights) {
+			w = in.FeatureWeights[y]
+		}
+		if w <= 0 {
+			continue
+		}
+
+		d += w * diff * diff
+	}
+
+	if in.DistanceMethod == TEuclidianSquared {
+		return d
+	}
+
+	return math.Sqrt(d)
+}
+
+//
+// nearestEuclidian return at most `k` rows that is nearest to `instance`,
+// sorted ascending by distance. Rows with equal distance is ordered by their
+// index in `samples`.
+//
+// Each range of samples is searched concurrently, by keeping the `k` nearest
+// rows in max-heap, then the result of each range is merged into one heap.
+//
+func (in *Runtime) nearestEuclidian(samples *tabula.Rows,
+	instance *tabula.Row, k int,
+) (
+	kneighbors Neighbors,
+) {
+	if k <= 0 {
+		return
+	}
+
+	nrow := len(*samples)
+
+	nworker := runtime.GOMAXPROCS(0)
+	if nworker > nrow/minRowsPerWorker {
+		nworker = nrow / minRowsPerWorker
+	}
+	if nworker < 1 {
+		nworker = 1
+	}
+
+	size := (nrow + nworker - 1) / nworker
+	locals := make([]maxHeap, nworker)
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < nworker; w++ {
+		start := w * size
+		end := start + size
+		if end > nrow {
+			end = nrow
+		}
+
+		wg.Add(1)
+		go func(w, start int, rows tabula.Rows) {
+			defer wg.Done()
+
+			for x, row := range rows {
+				d := in.euclidianDistance(row, instance)
+				if d != 0 {
+					locals[w].pushBounded(row, d, start+x, k)
+				}
+			}
+		}(w, start, (*samples)[start:end])
+	}
+
+	wg.Wait()
+
+	merged := maxHeap{}
+	for _, local := range locals {
+		for x, row := range local.rows {
+			merged.pushBounded(row, local.distances[x],
+				local.ids[x], k)
 		}
 	}
 
-	sort.Sort(&in.AllNeighbors)
+	return merged.sorted()
 }
 
 /*
-FindNeighbors Given sample set and an instance, return the nearest neighbors as
-a slice of neighbors.
+FindNeighbors Given sample set and an instance, return the K nearest neighbors
+as a slice of neighbors, sorted ascending by distance.
+
+If KeepAllNeighbors is true, the distance to all samples will be saved in
+AllNeighbors.
 */
 func (in *Runtime) FindNeighbors(samples *tabula.Rows, instance *tabula.Row) (
 	kneighbors Neighbors,
+) {
+	return in.FindNeighborsN(samples, instance, in.K)
+}
+
+/*
+FindNeighborsN Given sample set and an instance, return the first `n` nearest
+neighbors as a slice of neighbors, sorted ascending by distance.
+Neighbors with equal distance is ordered by their index in `samples`, so the
+result is deterministic.
+The number of returned neighbors may less than `n` if there is not enough
+samples.
+
+If KeepAllNeighbors is true, the distance to all samples will be saved in
+AllNeighbors.
+*/
+func (in *Runtime) FindNeighborsN(samples *tabula.Rows, instance *tabula.Row,
+	n int,
+) (
+	kneighbors Neighbors,
 ) {
 	// Reset current input neighbours
 	in.AllNeighbors = Neighbors{}
 
+	if !in.KeepAllNeighbors {
+		switch in.DistanceMethod {
+		case TEuclidianDistance, TEuclidianSquared:
+			kneighbors = in.nearestEuclidian(samples, instance, n)
+		}
+
+		if DEBUG >= 2 {
+			fmt.Println("[knn] n neighbors:", kneighbors.Len())
+		}
+
+		return
+	}
+
 	switch in.DistanceMethod {
-	case TEuclidianDistance:
+	case TEuclidianDistance, TEuclidianSquared:
 		in.ComputeEuclidianDistance(samples, instance)
 	}
 
 	// Make sure number of neighbors is greater than request.
-	minK := in.AllNeighbors.Len()
-	if minK > in.K {
-		minK = in.K
+	minN := in.AllNeighbors.Len()
+	if minN > n {
+		minN = n
 	}
 
 	if DEBUG >= 2 {
 		fmt.Println("[knn] all neighbors:", in.AllNeighbors.Len())
 	}
 
-	kneighbors = in.AllNeighbors.SelectRange(0, minK)
+	kneighbors = in.AllNeighbors.SelectRange(0, minN)
 
 	if DEBUG >= 2 {
-		fmt.Println("[knn] k neighbors:", kneighbors.Len())
+		fmt.Println("[knn] n neighbors:", kneighbors.Len())
 	}
 
 	return
@@ -0,0 +1,45 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package knn_test
+
+import (
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/knn"
+	"github.com/shuLhan/tabula"
+	"runtime"
+	"testing"
+)
+
+func benchmarkFindNeighbors(b *testing.B, nproc int) {
+	dataset := tabula.Dataset{}
+	_, e := dsv.SimpleRead("../testdata/phoneme/phoneme.dsv", &dataset)
+	if nil != e {
+		b.Fatal(e)
+	}
+
+	knnIn := knn.Runtime{
+		DistanceMethod: knn.TEuclidianDistance,
+		ClassIndex:     5,
+		K:              5,
+	}
+
+	rows := dataset.GetRows()
+
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(nproc))
+
+	b.ResetTimer()
+
+	for x := 0; x < b.N; x++ {
+		knnIn.FindNeighbors(rows, (*rows)[x%len(*rows)])
+	}
+}
+
+func BenchmarkFindNeighborsPhoneme1(b *testing.B) {
+	benchmarkFindNeighbors(b, 1)
+}
+
+func BenchmarkFindNeighborsPhonemeN(b *testing.B) {
+	benchmarkFindNeighbors(b, runtime.NumCPU())
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/shuLhan/dsv"
 	"github.com/shuLhan/go-mining/knn"
 	"github.com/shuLhan/tabula"
+	"math"
 	"reflect"
 	"runtime/debug"
 	"testing"
@@ -27,16 +28,16 @@ func TestComputeEuclidianDistance(t *testing.T) {
 	var exp = []string{
 		`[0.302891 0.608544 0.47413 1.42718 -0.811085 1]`,
 		`[0.243474 0.505146 0.472892 1.34802 -0.844252 1]` +
+			`[0.214331 0.546086 0.414773 1.38542 -0.702336 1]` +
 			`[0.202343 0.485983 0.527533 1.47307 -0.809672 1]` +
 			`[0.215496 0.523418 0.51719 1.43548 -0.933981 1]` +
-			`[0.214331 0.546086 0.414773 1.38542 -0.702336 1]` +
-			`[0.301676 0.554505 0.594757 1.21258 -0.873084 1]`,
+			`[0.187113 0.421943 0.393481 1.39996 -0.747699 1]`,
 	}
-	var expDistances = "[0.5257185558832786" +
-		" 0.5690474496911485" +
-		" 0.5888777462258191" +
-		" 0.6007362149895741" +
-		" 0.672666336306493]"
+	var expDistances = "[0.1469333672996028" +
+		" 0.1698156983732658" +
+		" 0.1734671960429407" +
+		" 0.17863594911719197" +
+		" 0.24390049832257407]"
 
 	// Reading data
 	dataset := tabula.Dataset{}
@@ -70,3 +71,268 @@ func TestComputeEuclidianDistance(t *testing.T) {
 	got = fmt.Sprint(*distances)
 	assert(t, expDistances, got, true)
 }
+
+func TestFindNeighborsHeap(t *testing.T) {
+	dataset := tabula.Dataset{}
+	_, e := dsv.SimpleRead("../testdata/phoneme/phoneme.dsv", &dataset)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	rows := dataset.GetRows()
+
+	knnAll := knn.Runtime{
+		DistanceMethod:   knn.TEuclidianDistance,
+		ClassIndex:       5,
+		K:                7,
+		KeepAllNeighbors: true,
+	}
+	knnHeap := knn.Runtime{
+		DistanceMethod: knn.TEuclidianDistance,
+		ClassIndex:     5,
+		K:              7,
+	}
+
+	for _, x := range []int{0, 100, 1000} {
+		instance := (*rows)[x]
+
+		exp := knnAll.FindNeighbors(rows, instance)
+		got := knnHeap.FindNeighbors(rows, instance)
+
+		assert(t, exp.Distances(), got.Distances(), true)
+		assert(t, 0, knnHeap.AllNeighbors.Len(), true)
+	}
+}
+
+func TestFindNeighborsN(t *testing.T) {
+	dataset := tabula.Dataset{}
+	_, e := dsv.SimpleRead("../testdata/phoneme/phoneme.dsv", &dataset)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	knnIn := knn.Runtime{
+		DistanceMethod: knn.TEuclidianDistance,
+		ClassIndex:     5,
+		K:              5,
+	}
+
+	rows := dataset.GetRows()
+	instance := (*rows)[0]
+
+	kneighbors := knnIn.FindNeighbors(rows, instance)
+	assert(t, knnIn.K, kneighbors.Len(), true)
+
+	neighbors := knnIn.FindNeighborsN(rows, instance, knnIn.K+1)
+	assert(t, knnIn.K+1, neighbors.Len(), true)
+
+	// The first K neighbors must be equal to FindNeighbors.
+	first := neighbors.SelectRange(0, knnIn.K)
+	assert(t, kneighbors.Distances(), first.Distances(), true)
+
+	// Requesting more than number of samples return all samples, except
+	// the instance itself.
+	small := (*rows)[0:4]
+	neighbors = knnIn.FindNeighborsN(&small, instance, 10)
+	assert(t, 3, neighbors.Len(), true)
+}
+
+func TestEuclidianSquared(t *testing.T) {
+	dataset := tabula.Dataset{}
+	_, e := dsv.SimpleRead("../testdata/phoneme/phoneme.dsv", &dataset)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	rows := dataset.GetRows()
+
+	for _, keepAll := range []bool{false, true} {
+		knnDist := knn.Runtime{
+			DistanceMethod:   knn.TEuclidianDistance,
+			ClassIndex:       5,
+			K:                7,
+			KeepAllNeighbors: keepAll,
+		}
+		knnSquared := knn.Runtime{
+			DistanceMethod:   knn.TEuclidianSquared,
+			ClassIndex:       5,
+			K:                7,
+			KeepAllNeighbors: keepAll,
+		}
+
+		for _, x := range []int{0, 100, 1000} {
+			instance := (*rows)[x]
+
+			exp := knnDist.FindNeighbors(rows, instance)
+			got := knnSquared.FindNeighbors(rows, instance)
+
+			assert(t, exp.Rows(), got.Rows(), true)
+
+			expDistances := *exp.Distances()
+			gotDistances := *got.Distances()
+			for y, d := range expDistances {
+				if math.Abs(d*d-gotDistances[y]) > 1e-9 {
+					t.Fatalf("Expecting squared distance %f, got %f",
+						d*d, gotDistances[y])
+				}
+			}
+		}
+	}
+}
+
+func TestFindNeighborsTie(t *testing.T) {
+	// All samples has equal distance to instance, alternating on both
+	// side. The number of samples is large enough to be computed
+	// concurrently.
+	samples := tabula.Rows{}
+	for x := 0; x < 2000; x++ {
+		v := 1.0
+		if x%2 == 1 {
+			v = -1
+		}
+
+		row := tabula.Row{}
+		row.PushBack(tabula.NewRecordReal(v))
+		row.PushBack(tabula.NewRecordString("a"))
+
+		samples.PushBack(&row)
+	}
+
+	instance := tabula.Row{}
+	instance.PushBack(tabula.NewRecordReal(0))
+	instance.PushBack(tabula.NewRecordString("a"))
+
+	for _, keepAll := range []bool{false, true} {
+		knnIn := knn.Runtime{
+			DistanceMethod:   knn.TEuclidianDistance,
+			ClassIndex:       1,
+			K:                5,
+			KeepAllNeighbors: keepAll,
+		}
+
+		for n := 0; n < 3; n++ {
+			kneighbors := knnIn.FindNeighbors(&samples, &instance)
+
+			assert(t, knnIn.K, kneighbors.Len(), true)
+
+			for x := 0; x < kneighbors.Len(); x++ {
+				if kneighbors.Row(x) != samples[x] {
+					t.Fatalf("Expecting neighbor %d is sample"+
+						" %d, keep all: %v", x, x,
+						keepAll)
+				}
+			}
+		}
+	}
+}
+
+func TestFeatureWeights(t *testing.T) {
+	samples := tabula.Rows{}
+	for _, vals := range [][]float64{{1, 10}, {5, 0}} {
+		row := tabula.Row{}
+		for _, v := range vals {
+			row.PushBack(tabula.NewRecordReal(v))
+		}
+		row.PushBack(tabula.NewRecordString("a"))
+
+		samples.PushBack(&row)
+	}
+
+	instance := tabula.Row{}
+	instance.PushBack(tabula.NewRecordReal(0))
+	instance.PushBack(tabula.NewRecordReal(0))
+	instance.PushBack(tabula.NewRecordString("a"))
+
+	knnIn := knn.Runtime{
+		DistanceMethod: knn.TEuclidianDistance,
+		ClassIndex:     2,
+		K:              1,
+	}
+
+	kneighbors := knnIn.FindNeighbors(&samples, &instance)
+
+	assert(t, samples[1], kneighbors.Row(0), true)
+
+	// Ignore the second feature.
+	knnIn.FeatureWeights = []float64{1, 0}
+
+	kneighbors = knnIn.FindNeighbors(&samples, &instance)
+
+	assert(t, samples[0], kneighbors.Row(0), true)
+}
+
+func TestEuclidianKnownValues(t *testing.T) {
+	newRow := func(x, y float64) *tabula.Row {
+		row := tabula.Row{}
+		row.PushBack(tabula.NewRecordReal(x))
+		row.PushBack(tabula.NewRecordReal(y))
+		row.PushBack(tabula.NewRecordString("a"))
+		return &row
+	}
+
+	samples := tabula.Rows{newRow(3, 4)}
+	instance := newRow(0, 0)
+
+	cases := []struct {
+		method int
+		exp    float64
+	}{
+		{knn.TEuclidianSquared, 25},
+		{knn.TEuclidianDistance, 5},
+	}
+
+	for _, c := range cases {
+		knnIn := knn.Runtime{
+			DistanceMethod: c.method,
+			ClassIndex:     2,
+			K:              1,
+		}
+
+		kneighbors := knnIn.FindNeighbors(&samples, instance)
+
+		assert(t, []float64{c.exp}, *kneighbors.Distances(), true)
+	}
+}
+
+func TestFeatureWeightsSquared(t *testing.T) {
+	samples := tabula.Rows{}
+	for _, vals := range [][]float64{{1, 0}, {0, 3}} {
+		row := tabula.Row{}
+		for _, v := range vals {
+			row.PushBack(tabula.NewRecordReal(v))
+		}
+		row.PushBack(tabula.NewRecordString("a"))
+
+		samples.PushBack(&row)
+	}
+
+	instance := tabula.Row{}
+	instance.PushBack(tabula.NewRecordReal(0))
+	instance.PushBack(tabula.NewRecordReal(0))
+	instance.PushBack(tabula.NewRecordString("a"))
+
+	// The weight multiply the squared difference, so the distance to
+	// the first sample is 4*1*1 = 4 and to the second sample is
+	// 1*3*3 = 9. Scaling the difference by weight before squaring it
+	// will make the second sample nearer.
+	knnIn := knn.Runtime{
+		DistanceMethod: knn.TEuclidianSquared,
+		ClassIndex:     2,
+		K:              2,
+		FeatureWeights: []float64{4, 1},
+	}
+
+	kneighbors := knnIn.FindNeighbors(&samples, &instance)
+
+	assert(t, samples[0], kneighbors.Row(0), true)
+	assert(t, []float64{4, 9}, *kneighbors.Distances(), true)
+
+	// Negative weight is treated as zero.
+	knnIn.FeatureWeights = []float64{0, 1}
+	exp := knnIn.FindNeighbors(&samples, &instance)
+
+	knnIn.FeatureWeights = []float64{-1, 1}
+	got := knnIn.FindNeighbors(&samples, &instance)
+
+	assert(t, exp.Distances(), got.Distances(), true)
+}
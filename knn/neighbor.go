@@ -10,7 +10,8 @@ import (
 
 /*
 Neighbors is a mapping between sample and their distance.
-This type implement the sort interface.
+This type implement the sort interface. Use sort.Stable to keep the order
+of neighbors with equal distance.
 */
 type Neighbors struct {
 	// rows contain pointer to rows.
@@ -112,9 +113,23 @@ func (neighbors *Neighbors) SelectRange(start, end int) (newn Neighbors) {
 //
 // SelectWhere return all neighbors where row value at index `idx` is equal
 // to string `val`.
+// If no neighbor match, it will return an empty neighbors, where Rows and
+// Distances is not nil. Row that does not have column at index `idx` is
+// skipped.
 //
 func (neighbors *Neighbors) SelectWhere(idx int, val string) (newn Neighbors) {
+	newn.rows = make([]*tabula.Row, 0)
+	newn.distances = make([]float64, 0)
+
+	if idx < 0 {
+		return
+	}
+
 	for x, row := range neighbors.rows {
+		if row == nil || idx >= len(*row) {
+			continue
+		}
+
 		colval := (*row)[idx].String()
 
 		if colval == val {
@@ -124,11 +139,41 @@ func (neighbors *Neighbors) SelectWhere(idx int, val string) (newn Neighbors) {
 	return
 }
 
+//
+// ClassDistribution return the number of neighbors for each class, where
+// class is the row value at index `classIndex`.
+// Row that does not have column at index `classIndex` is skipped.
+//
+func (neighbors *Neighbors) ClassDistribution(classIndex int) (
+	dist map[string]int,
+) {
+	dist = make(map[string]int)
+
+	if classIndex < 0 {
+		return
+	}
+
+	for _, row := range neighbors.rows {
+		if row == nil || classIndex >= len(*row) {
+			continue
+		}
+
+		dist[(*row)[classIndex].String()]++
+	}
+	return
+}
+
 //
 // Contain return true if `row` is in neighbors and their index, otherwise
 // return false and -1.
+// If neighbors contain duplicate rows, the index of the first row is
+// returned. For empty neighbors or nil row, it will return false and -1.
 //
 func (neighbors *Neighbors) Contain(row *tabula.Row) (bool, int) {
+	if row == nil {
+		return false, -1
+	}
+
 	for x, xrow := range neighbors.rows {
 		if xrow.IsEqual(row) {
 			return true, x
@@ -139,9 +184,10 @@ func (neighbors *Neighbors) Contain(row *tabula.Row) (bool, int) {
 
 //
 // Replace neighbor at index `idx` with new row and distance value.
+// If `idx` is out of range, neighbors will not be changed.
 //
 func (neighbors *Neighbors) Replace(idx int, row *tabula.Row, distance float64) {
-	if idx > len(neighbors.rows) {
+	if idx < 0 || idx >= len(neighbors.rows) {
 		return
 	}
 
@@ -81,3 +81,127 @@ func TestSort(t *testing.T) {
 
 	assert(t, exp.Rows(), neighbors.Rows(), true)
 }
+
+func TestContainEmpty(t *testing.T) {
+	neighbors := createNeigbours()
+	row := neighbors.Row(0)
+
+	neighbors = knn.Neighbors{}
+
+	isin, idx := neighbors.Contain(row)
+
+	assert(t, false, isin, true)
+	assert(t, -1, idx, true)
+
+	neighbors = createNeigbours()
+
+	isin, idx = neighbors.Contain(nil)
+
+	assert(t, false, isin, true)
+	assert(t, -1, idx, true)
+}
+
+func TestContainDuplicate(t *testing.T) {
+	neighbors := createNeigbours()
+	row := neighbors.Row(1).Clone()
+
+	neighbors.Add(row, 5)
+
+	isin, idx := neighbors.Contain(row)
+
+	assert(t, true, isin, true)
+	assert(t, 1, idx, true)
+}
+
+func TestSelectWhere(t *testing.T) {
+	neighbors := createNeigbours()
+	classIdx := len(dataFloat64[0]) - 1
+	class := (*neighbors.Row(0))[classIdx].String()
+
+	got := neighbors.SelectWhere(classIdx, class)
+
+	assert(t, neighbors.Len(), got.Len(), true)
+
+	// No neighbor match.
+	got = neighbors.SelectWhere(classIdx, "unknown")
+
+	assert(t, 0, got.Len(), true)
+	assert(t, false, *got.Rows() == nil, true)
+	assert(t, false, *got.Distances() == nil, true)
+
+	// Empty neighbors.
+	empty := knn.Neighbors{}
+	got = empty.SelectWhere(classIdx, class)
+
+	assert(t, 0, got.Len(), true)
+	assert(t, false, *got.Rows() == nil, true)
+
+	// Index out of range.
+	got = neighbors.SelectWhere(classIdx+1, class)
+
+	assert(t, 0, got.Len(), true)
+}
+
+func TestReplace(t *testing.T) {
+	neighbors := createNeigbours()
+	exp := createNeigbours()
+	row := neighbors.Row(0).Clone()
+
+	neighbors.Replace(-1, row, 10)
+	neighbors.Replace(neighbors.Len(), row, 10)
+
+	assert(t, exp.Rows(), neighbors.Rows(), true)
+	assert(t, exp.Distances(), neighbors.Distances(), true)
+
+	neighbors.Replace(1, row, 10)
+
+	assert(t, row, neighbors.Row(1), true)
+	assert(t, float64(10), neighbors.Distance(1), true)
+}
+
+func TestClassDistribution(t *testing.T) {
+	classes := []string{"safe", "noise", "safe", "danger", "safe", "noise"}
+
+	neighbors := knn.Neighbors{}
+	for x, class := range classes {
+		row := tabula.Row{}
+		row.PushBack(tabula.NewRecordReal(float64(x)))
+		row.PushBack(tabula.NewRecordString(class))
+
+		neighbors.Add(&row, float64(x))
+	}
+
+	exp := map[string]int{
+		"safe":   3,
+		"noise":  2,
+		"danger": 1,
+	}
+
+	assert(t, exp, neighbors.ClassDistribution(1), true)
+
+	// Index out of range.
+	assert(t, map[string]int{}, neighbors.ClassDistribution(2), true)
+	assert(t, map[string]int{}, neighbors.ClassDistribution(-1), true)
+
+	// Empty neighbors.
+	empty := knn.Neighbors{}
+
+	assert(t, map[string]int{}, empty.ClassDistribution(1), true)
+}
+
+func TestSortStable(t *testing.T) {
+	neighbors := createNeigbours()
+
+	// Set duplicate distances, so rows 1, 3 and 0, 2, 4 is tie.
+	dists := neighbors.Distances()
+	for x := range *dists {
+		(*dists)[x] = float64(2 - x%2)
+	}
+
+	sort.Stable(&neighbors)
+
+	exp := createNeigboursByIdx([]int{1, 3, 0, 2, 4})
+
+	assert(t, exp.Rows(), neighbors.Rows(), true)
+	assert(t, []float64{1, 1, 2, 2, 2}, *neighbors.Distances(), true)
+}
@@ -0,0 +1,114 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//
+// Package pipeline chain the resampling of training samples and building of
+// classifier, and evaluate the classifier using test samples.
+//
+package pipeline
+
+import (
+	"errors"
+	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/go-mining/resampling"
+	"github.com/shuLhan/tabula"
+)
+
+var (
+	// ErrNoClassifier will tell you when the classifier in pipeline is
+	// not set.
+	ErrNoClassifier = errors.New("pipeline: classifier is not set")
+	// ErrNoInput will tell you when training or test samples is empty.
+	ErrNoInput = errors.New("pipeline: input samples is empty")
+)
+
+//
+// Pipeline compose the resampling module and classifier.
+//
+type Pipeline struct {
+	// Resampler is used to generate synthetic samples from training
+	// samples. If its nil, the training samples is not resampled.
+	Resampler resampling.Resampler
+	// Classifier is the model that will be build using training samples
+	// and synthetic samples.
+	Classifier classifier.Classifier
+}
+
+/*
+Run will resample the training samples, build the classifier, and return the
+statistic of classifying the test samples.
+
+Algorithm,
+
+(1) If Resampler is not nil,
+(1.1) run resampling on training samples, and
+(1.2) create new training samples which contain the training samples and
+their synthetic samples.
+(2) Build the classifier using training samples.
+(3) Classify each row in test samples.
+(4) Compute the statistic from confusion matrix of test samples, where the
+positive class is the first class in value space of training samples.
+*/
+func (pipe *Pipeline) Run(train, test tabula.ClasetInterface) (
+	stat *classifier.Stat, e error,
+) {
+	if pipe.Classifier == nil {
+		return nil, ErrNoClassifier
+	}
+	if train == nil || test == nil || train.GetNRow() <= 0 ||
+		test.GetNRow() <= 0 {
+		return nil, ErrNoInput
+	}
+
+	stat = &classifier.Stat{}
+	stat.Start()
+
+	vs := train.GetClassValueSpace()
+
+	// (1)
+	if pipe.Resampler != nil {
+		// (1.1)
+		e = pipe.Resampler.Resampling(train)
+		if e != nil {
+			return nil, e
+		}
+
+		// (1.2)
+		trainset := train.Clone().(tabula.ClasetInterface)
+		for _, row := range *train.GetRows() {
+			trainset.PushRow(row.Clone())
+		}
+		for _, row := range *pipe.Resampler.GetSynthetics().GetRows() {
+			trainset.PushRow(row.Clone())
+		}
+		trainset.RecountMajorMinor()
+
+		train = trainset
+	}
+
+	// (2)
+	e = pipe.Classifier.Build(train)
+	if e != nil {
+		return nil, e
+	}
+
+	// (3)
+	actuals := test.GetClassAsStrings()
+	predicts := make([]string, 0, len(actuals))
+
+	for _, row := range *test.GetRows() {
+		predicts = append(predicts, pipe.Classifier.Classify(row))
+	}
+
+	// (4)
+	cm := classifier.CM{}
+	cm.ComputeStrings(vs, actuals, predicts)
+
+	rt := classifier.Runtime{}
+	rt.ComputeStatFromCM(stat, &cm)
+
+	stat.End()
+
+	return stat, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pipeline_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/classifier"
+	"github.com/shuLhan/go-mining/classifier/rf"
+	"github.com/shuLhan/go-mining/dataset"
+	"github.com/shuLhan/go-mining/pipeline"
+	"github.com/shuLhan/go-mining/resampling/smote"
+	"github.com/shuLhan/tabula"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	samples := tabula.Claset{}
+	_, e := dsv.SimpleRead("../testdata/phoneme/phoneme.dsv", &samples)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	train, test := dataset.TrainTestSplit(&samples, 0.7, 1, true)
+
+	pipe := pipeline.Pipeline{
+		Resampler: smote.New(100, 5, samples.GetClassIndex()),
+		Classifier: &rf.Runtime{
+			Runtime: classifier.Runtime{
+				OOBStatsFile: "phoneme.oob",
+			},
+			NTree: 10,
+		},
+	}
+
+	_, e = pipe.Run(train, nil)
+	if e != pipeline.ErrNoInput {
+		t.Fatalf("Expecting error %v, got %v", pipeline.ErrNoInput, e)
+	}
+
+	stat, e := pipe.Run(train, test)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	fmt.Println("[pipeline_test] stat:", stat)
+
+	ntest := int64(test.GetNRow())
+	if stat.TP+stat.FP+stat.TN+stat.FN != ntest {
+		t.Fatalf("Expecting %d classified samples, got %d", ntest,
+			stat.TP+stat.FP+stat.TN+stat.FN)
+	}
+	if stat.Accuracy <= 0 || stat.TPRate <= 0 {
+		t.Fatalf("Expecting accuracy and TP rate is computed, got %f"+
+			" and %f", stat.Accuracy, stat.TPRate)
+	}
+}
@@ -27,6 +27,17 @@ type Interface interface {
 	GetSynthetics() tabula.DatasetInterface
 }
 
+//
+// Resampler define common methods of resampling algorithm (e.g. SMOTE,
+// LN-SMOTE), so the resampling module can be used uniformly.
+// Resampling will generate synthetic samples from `dataset`, which can be
+// retrieved later using GetSynthetics.
+//
+type Resampler interface {
+	Interface
+	Resampling(dataset tabula.ClasetInterface) error
+}
+
 //
 // WriteSynthetics will write synthetic samples in resampling module `ri` into
 // `file`.
@@ -50,3 +61,37 @@ func WriteSynthetics(ri Interface, file string) (e error) {
 
 	return writer.Close()
 }
+
+//
+// WriteResampled will write the rows in `original` dataset followed by
+// synthetic samples in resampling module `ri` into `file`, as one resampled
+// dataset.
+//
+func WriteResampled(ri Interface, file string,
+	original tabula.ClasetInterface,
+) (e error) {
+	writer, e := dsv.NewWriter("")
+	if nil != e {
+		return
+	}
+
+	e = writer.OpenOutput(file)
+	if e != nil {
+		return
+	}
+
+	sep := dsv.DefSeparator
+	_, e = writer.WriteRawRows(original.GetDataAsRows(), &sep)
+	if e != nil {
+		writer.Close()
+		return
+	}
+
+	_, e = writer.WriteRawDataset(ri.GetSynthetics(), &sep)
+	if e != nil {
+		writer.Close()
+		return
+	}
+
+	return writer.Close()
+}
@@ -0,0 +1,44 @@
+// Copyright 2016 Mhd Sulhan <ms@kilabit.info>. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resampling_test
+
+import (
+	"fmt"
+	"github.com/shuLhan/dsv"
+	"github.com/shuLhan/go-mining/resampling"
+	"github.com/shuLhan/go-mining/resampling/lnsmote"
+	"github.com/shuLhan/go-mining/resampling/smote"
+	"github.com/shuLhan/tabula"
+	"testing"
+)
+
+func TestResampler(t *testing.T) {
+	dataset := tabula.Claset{}
+	_, e := dsv.SimpleRead("../testdata/phoneme/phoneme.dsv", &dataset)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	resamplers := []resampling.Resampler{
+		smote.New(100, 5, 5),
+		lnsmote.New(100, 5, 5, "1", ""),
+	}
+
+	for x, resampler := range resamplers {
+		e = resampler.Resampling(&dataset)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		n := resampler.GetSynthetics().Len()
+
+		fmt.Printf("[resampling_test] %d # synthetic: %d\n", x, n)
+
+		if n <= 0 {
+			t.Fatalf("Expecting synthetic samples on resampler %d,"+
+				" got none", x)
+		}
+	}
+}
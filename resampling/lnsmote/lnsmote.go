@@ -18,7 +18,6 @@ import (
 	"github.com/shuLhan/go-mining/knn"
 	"github.com/shuLhan/go-mining/resampling/smote"
 	"github.com/shuLhan/tabula"
-	"math/rand"
 	"os"
 	"strconv"
 )
@@ -98,6 +97,7 @@ func (in *Runtime) Init(dataset tabula.ClasetInterface) {
 		in.ClassMinor)
 
 	in.outliers = make(tabula.Rows, 0)
+	in.Synthetics = tabula.Dataset{}
 
 	if DEBUG >= 1 {
 		fmt.Println("[lnsmote] n:", in.NSynthetic)
@@ -109,6 +109,10 @@ func (in *Runtime) Init(dataset tabula.ClasetInterface) {
 // Resampling will run resampling process on dataset and return the synthetic
 // samples.
 //
+// The synthetic samples are always kept in memory and can be accessed through
+// `Synthetics` or `GetSynthetics()`. They will be written to `SyntheticFile`
+// and the outliers to `OutliersFile` only if the file name is not empty.
+//
 func (in *Runtime) Resampling(dataset tabula.ClasetInterface) (
 	e error,
 ) {
@@ -141,6 +145,9 @@ func (in *Runtime) Resampling(dataset tabula.ClasetInterface) (
 
 	if in.SyntheticFile != "" {
 		e = in.Write(in.SyntheticFile)
+		if e != nil {
+			return
+		}
 	}
 	if in.OutliersFile != "" && in.outliers.Len() > 0 {
 		e = in.writeOutliers()
@@ -149,6 +156,14 @@ func (in *Runtime) Resampling(dataset tabula.ClasetInterface) (
 	return
 }
 
+//
+// GetOutliers return all samples that is detected as outliers in the last
+// resampling process.
+//
+func (in *Runtime) GetOutliers() *tabula.Rows {
+	return &in.outliers
+}
+
 //
 // createSynthetic will create synthetics row from original row `p` and their
 // `neighbors`.
@@ -157,7 +172,7 @@ func (in *Runtime) createSynthetic(p *tabula.Row, neighbors knn.Neighbors) (
 	synthetic *tabula.Row,
 ) {
 	// choose one of the K nearest neighbors
-	randIdx := rand.Intn(neighbors.Len())
+	randIdx := in.Rand.Intn(neighbors.Len())
 	n := neighbors.Row(randIdx)
 
 	// Check if synthetic sample can be created from p and n.
@@ -224,7 +239,14 @@ func (in *Runtime) safeLevel(p *tabula.Row) knn.Neighbors {
 // safeLevel2 return the minority neighbors between sample `p` and `n`.
 //
 func (in *Runtime) safeLevel2(p, n *tabula.Row) knn.Neighbors {
-	neighbors := in.FindNeighbors(in.datasetRows, n)
+	// Find K+1 neighbors, where the last one is used to replace p.
+	allNeighbors := in.FindNeighborsN(in.datasetRows, n, in.K+1)
+
+	nk := allNeighbors.Len()
+	if nk > in.K {
+		nk = in.K
+	}
+	neighbors := allNeighbors.SelectRange(0, nk)
 
 	// check if n is in minority class.
 	nIsMinor := (*n)[in.ClassIndex].IsEqualToString(in.ClassMinor)
@@ -241,12 +263,18 @@ func (in *Runtime) safeLevel2(p, n *tabula.Row) knn.Neighbors {
 			fmt.Println("[lnsmote] Replacing ", pidx, " in ", neighbors)
 		}
 
-		row := in.AllNeighbors.Row(in.K + 1)
-		dist := in.AllNeighbors.Distance(in.K + 1)
-		neighbors.Replace(pidx, row, dist)
+		// If there is not enough samples, there is no neighbor
+		// for replacement.
+		if allNeighbors.Len() > in.K {
+			row := allNeighbors.Row(in.K)
+			dist := allNeighbors.Distance(in.K)
+			neighbors.Replace(pidx, row, dist)
 
-		if DEBUG >= 2 {
-			fmt.Println("[lnsmote] Replacement ", neighbors)
+			if DEBUG >= 2 {
+				fmt.Println("[lnsmote] Replacement ", neighbors)
+			}
+		} else if DEBUG >= 1 {
+			fmt.Println("[lnsmote] No neighbor for replacement")
 		}
 	}
 
@@ -268,11 +296,11 @@ func (in *Runtime) randomGap(p, n *tabula.Row, lenslp, lensln int) (
 
 	slratio := float64(lenslp) / float64(lensln)
 	if slratio == 1 {
-		delta = rand.Float64()
+		delta = in.Rand.Float64()
 	} else if slratio > 1 {
-		delta = rand.Float64() * (1 / slratio)
+		delta = in.Rand.Float64() * (1 / slratio)
 	} else {
-		delta = 1 - rand.Float64()*slratio
+		delta = 1 - in.Rand.Float64()*slratio
 	}
 
 	return delta
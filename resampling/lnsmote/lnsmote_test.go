@@ -5,10 +5,14 @@
 package lnsmote_test
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/shuLhan/dsv"
 	"github.com/shuLhan/go-mining/resampling/lnsmote"
 	"github.com/shuLhan/tabula"
+	"io/ioutil"
+	"math/rand"
+	"os"
 	"testing"
 )
 
@@ -62,3 +66,146 @@ func TestLNSmote(t *testing.T) {
 		t.Fatal(e)
 	}
 }
+
+//
+// listFiles return the name of all files in current directory.
+//
+func listFiles(t *testing.T) (names map[string]bool) {
+	fis, e := ioutil.ReadDir(".")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	names = make(map[string]bool, len(fis))
+	for _, fi := range fis {
+		names[fi.Name()] = true
+	}
+	return names
+}
+
+func TestLNSmoteInMemory(t *testing.T) {
+	dataset := tabula.Claset{}
+	_, e := dsv.SimpleRead(fcfg, &dataset)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	before := listFiles(t)
+
+	// No synthetic or outliers file, everything is kept in memory.
+	lnsmoteRun := lnsmote.New(100, 5, 5, "1", "")
+
+	e = lnsmoteRun.Resampling(&dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	for name := range listFiles(t) {
+		if !before[name] {
+			t.Fatalf("Expecting no file created, got %s", name)
+		}
+	}
+
+	nsynt := lnsmoteRun.Synthetics.Len()
+
+	fmt.Println("[lnsmote_test] # synthetic in memory:", nsynt)
+
+	if nsynt <= 0 {
+		t.Fatal("Expecting synthetic samples, got none")
+	}
+	if lnsmoteRun.GetSynthetics().Len() != nsynt {
+		t.Fatal("Expecting GetSynthetics length", nsynt)
+	}
+
+	// Resampling again should not accumulate previous synthetics, with
+	// 100% oversampling there is at most one synthetic per minority.
+	e = lnsmoteRun.Resampling(&dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	nminor := dataset.GetMinorityRows().Len()
+	if lnsmoteRun.Synthetics.Len() > nminor {
+		t.Fatal("Synthetics is accumulated from previous run")
+	}
+}
+
+func TestLNSmoteTinyMinority(t *testing.T) {
+	dataset := tabula.Claset{}
+	_, e := dsv.SimpleRead(fcfg, &dataset)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	// Create dataset with only four minority samples, so each sample
+	// have less than K+2 neighbors.
+	minorset := tabula.SelectRowsWhere(&dataset, dataset.GetClassIndex(),
+		"1")
+
+	tiny, _, _, _ := tabula.RandomPickRows(minorset, 4, false)
+
+	tinyset := tiny.(tabula.ClasetInterface)
+	tinyset.SetClassIndex(dataset.GetClassIndex())
+
+	lnsmoteRun := lnsmote.New(100, 5, 5, "1", "")
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatal("Expecting no panic on tiny minority, got", r)
+		}
+	}()
+
+	e = lnsmoteRun.Resampling(tinyset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	nsynt := lnsmoteRun.Synthetics.Len()
+
+	fmt.Println("[lnsmote_test] # synthetic from tiny set:", nsynt)
+
+	// With 100% oversampling, each minority sample create at most one
+	// synthetic sample.
+	if nsynt > tinyset.GetNRow() {
+		t.Fatalf("Expecting at most %d synthetic, got %d",
+			tinyset.GetNRow(), nsynt)
+	}
+}
+
+func TestLNSmoteRand(t *testing.T) {
+	dataset := tabula.Claset{}
+	_, e := dsv.SimpleRead(fcfg, &dataset)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	files := []string{"lnsmote_seed1.csv", "lnsmote_seed2.csv"}
+	outs := make([][]byte, len(files))
+
+	for x, file := range files {
+		lnsmoteRun := lnsmote.New(100, 5, 5, "1", "")
+		lnsmoteRun.Rand = rand.New(rand.NewSource(1))
+
+		e = lnsmoteRun.Resampling(&dataset)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		e = lnsmoteRun.Write(file)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		outs[x], e = ioutil.ReadFile(file)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		_ = os.Remove(file)
+	}
+
+	if !bytes.Equal(outs[0], outs[1]) {
+		t.Fatal("Expecting identical synthetic samples with the same" +
+			" seed")
+	}
+}
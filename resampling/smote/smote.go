@@ -36,6 +36,11 @@ type Runtime struct {
 	NSynthetic int
 	// Synthetics contain output of resampling as synthetic samples.
 	Synthetics tabula.Dataset
+	// Rand random generator used for selecting neighbor and computing
+	// gap of synthetic samples. Set it with fixed seed to reproduce the
+	// same synthetic samples. If its nil, it will be created using
+	// current time as seed.
+	Rand *rand.Rand `json:"-"`
 }
 
 //
@@ -57,7 +62,10 @@ func New(percentOver, k, classIndex int) (smoteRun *Runtime) {
 // Init will recheck input and set to default value if its not valid.
 //
 func (smote *Runtime) Init() {
-	rand.Seed(time.Now().UnixNano())
+	if smote.Rand == nil {
+		seed := time.Now().UnixNano()
+		smote.Rand = rand.New(rand.NewSource(seed))
+	}
 
 	if smote.K <= 0 {
 		smote.K = resampling.DefaultK
@@ -76,13 +84,18 @@ func (smote *Runtime) GetSynthetics() tabula.DatasetInterface {
 
 /*
 populate will generate new synthetic sample using nearest neighbors.
+If neighbors is empty, no synthetic sample will be generated.
 */
 func (smote *Runtime) populate(instance *tabula.Row, neighbors knn.Neighbors) {
+	if neighbors.Len() <= 0 {
+		return
+	}
+
 	lenAttr := len(*instance)
 
 	for x := 0; x < smote.NSynthetic; x++ {
 		// choose one of the K nearest neighbors
-		n := rand.Intn(neighbors.Len())
+		n := smote.Rand.Intn(neighbors.Len())
 		sample := neighbors.Row(n)
 
 		newSynt := make(tabula.Row, lenAttr)
@@ -99,7 +112,7 @@ func (smote *Runtime) populate(instance *tabula.Row, neighbors knn.Neighbors) {
 			sv := sr.Float()
 
 			dif := sv - iv
-			gap := rand.Float64()
+			gap := smote.Rand.Float64()
 			newAttr := iv + (gap * dif)
 
 			record := &tabula.Record{}
@@ -114,8 +127,20 @@ func (smote *Runtime) populate(instance *tabula.Row, neighbors knn.Neighbors) {
 }
 
 //
-// Resampling will run resampling algorithm using values that has been defined
-// in `Runtime` and return list of synthetic samples.
+// Resampling will run resampling algorithm on minority class in `dataset`
+// using ResamplingRows. The class index is set from the dataset.
+//
+func (smote *Runtime) Resampling(dataset tabula.ClasetInterface) (e error) {
+	smote.ClassIndex = dataset.GetClassIndex()
+
+	dataset.RecountMajorMinor()
+
+	return smote.ResamplingRows(*dataset.GetMinorityRows())
+}
+
+//
+// ResamplingRows will run resampling algorithm using values that has been
+// defined in `Runtime` and return list of synthetic samples.
 //
 // The `dataset` must be samples of minority class not the whole dataset.
 //
@@ -132,7 +157,7 @@ func (smote *Runtime) populate(instance *tabula.Row, neighbors knn.Neighbors) {
 // (1.2) generate synthetic sample in neighbors.
 // (2) Write synthetic samples to file, only if `SyntheticFile` is not empty.
 //
-func (smote *Runtime) Resampling(dataset tabula.Rows) (e error) {
+func (smote *Runtime) ResamplingRows(dataset tabula.Rows) (e error) {
 	smote.Init()
 
 	if smote.PercentOver < 100 {
@@ -162,6 +187,88 @@ func (smote *Runtime) Resampling(dataset tabula.Rows) (e error) {
 	return
 }
 
+/*
+ResampleAll will oversample every class in `dataset` that have number of
+samples less than the majority class, until each of them have the same
+number of samples as the majority class. This is useful for multi-class
+imbalanced dataset, where SMOTE is applied to each minority class against
+the rest (one-vs-rest).
+Unlike Resampling, the `dataset` is the whole dataset and PercentOver is
+ignored. The class index is set from the dataset. Synthetic samples from the
+previous run is cleared.
+
+Algorithm,
+
+(0) Group rows in dataset by class.
+(1) Find the number of samples in majority class.
+(2) For each class,
+(2.1) skip the class if its have the same number of samples as majority
+class, or have less than two samples (no neighbor),
+(2.2) compute the number of synthetic samples needed, N = majority - n,
+where n is the number of samples in class,
+(2.3) for each sample in class, find k-nearest-neighbors of sample in the
+same class, and generate N/n synthetic samples,
+(2.4) generate one more synthetic samples for N%n samples in class that is
+selected randomly.
+(3) Write synthetic samples to file, only if `SyntheticFile` is not empty.
+*/
+func (smote *Runtime) ResampleAll(dataset tabula.ClasetInterface) (e error) {
+	smote.Init()
+
+	smote.ClassIndex = dataset.GetClassIndex()
+	smote.Synthetics = tabula.Dataset{}
+
+	// (0)
+	rows := dataset.GetDataAsRows()
+	classRows := rows.GroupByValue(smote.ClassIndex)
+
+	// (1)
+	nmajor := 0
+	for _, class := range classRows {
+		if len(class.Value) > nmajor {
+			nmajor = len(class.Value)
+		}
+	}
+
+	// (2)
+	for _, class := range classRows {
+		n := len(class.Value)
+
+		// (2.1)
+		if n >= nmajor || n < 2 {
+			continue
+		}
+
+		// (2.2)
+		nsynt := nmajor - n
+
+		// (2.3)
+		smote.NSynthetic = nsynt / n
+
+		neighbors := make([]knn.Neighbors, n)
+
+		for x, sample := range class.Value {
+			neighbors[x] = smote.FindNeighbors(&class.Value, sample)
+
+			smote.populate(sample, neighbors[x])
+		}
+
+		// (2.4)
+		smote.NSynthetic = 1
+
+		for _, x := range smote.Rand.Perm(n)[:nsynt%n] {
+			smote.populate(class.Value[x], neighbors[x])
+		}
+	}
+
+	// (3)
+	if smote.SyntheticFile != "" {
+		e = resampling.WriteSynthetics(smote, smote.SyntheticFile)
+	}
+
+	return
+}
+
 //
 // Write will write synthetic samples to file defined in `file`.
 //
@@ -169,6 +276,16 @@ func (smote *Runtime) Write(file string) error {
 	return resampling.WriteSynthetics(smote, file)
 }
 
+//
+// WriteResampled will write the rows in `original` dataset followed by
+// synthetic samples into `file`.
+//
+func (smote *Runtime) WriteResampled(file string,
+	original tabula.ClasetInterface,
+) error {
+	return resampling.WriteResampled(smote, file, original)
+}
+
 func (smote *Runtime) String() (s string) {
 	s = fmt.Sprintf("'smote' : {\n"+
 		"		'ClassIndex'     :%d\n"+
@@ -5,10 +5,14 @@
 package smote_test
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/shuLhan/dsv"
 	"github.com/shuLhan/go-mining/resampling/smote"
 	"github.com/shuLhan/tabula"
+	"io/ioutil"
+	"math/rand"
+	"os"
 	"testing"
 )
 
@@ -35,7 +39,7 @@ func TestSmote(t *testing.T) {
 
 	fmt.Println("[smote_test] # minority samples:", minorset.Len())
 
-	e = smot.Resampling(*minorset)
+	e = smot.ResamplingRows(*minorset)
 	if e != nil {
 		t.Fatal(e)
 	}
@@ -47,3 +51,145 @@ func TestSmote(t *testing.T) {
 		t.Fatal(e)
 	}
 }
+
+func TestResampleAll(t *testing.T) {
+	dataset := tabula.Claset{}
+
+	_, e := dsv.SimpleRead("../../testdata/forensic_glass/fgl.dsv",
+		&dataset)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	smot := smote.New(PercentOver, K, 0)
+
+	e = smot.ResampleAll(&dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	counts := make(map[string]int)
+	for _, row := range *dataset.GetRows() {
+		counts[(*row)[dataset.GetClassIndex()].String()]++
+	}
+
+	nmajor := 0
+	for _, n := range counts {
+		if n > nmajor {
+			nmajor = n
+		}
+	}
+
+	for _, row := range *smot.GetSynthetics().GetRows() {
+		counts[(*row)[dataset.GetClassIndex()].String()]++
+	}
+
+	fmt.Println("[smote_test] # samples per class:", counts)
+
+	for class, n := range counts {
+		if n != nmajor {
+			t.Fatalf("Expecting class %s have %d samples, got %d",
+				class, nmajor, n)
+		}
+	}
+}
+
+func TestSmoteRand(t *testing.T) {
+	dataset := tabula.Claset{}
+
+	_, e := dsv.SimpleRead(fcfg, &dataset)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	minorset := dataset.GetMinorityRows()
+	files := []string{"phoneme_smote_seed1.csv", "phoneme_smote_seed2.csv"}
+	outs := make([][]byte, len(files))
+
+	for x, file := range files {
+		smot := smote.New(PercentOver, K, 5)
+		smot.Rand = rand.New(rand.NewSource(1))
+
+		e = smot.ResamplingRows(*minorset)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		e = smot.Write(file)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		outs[x], e = ioutil.ReadFile(file)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		_ = os.Remove(file)
+	}
+
+	if len(outs[0]) <= 0 {
+		t.Fatal("Expecting synthetic samples, got none")
+	}
+	if !bytes.Equal(outs[0], outs[1]) {
+		t.Fatal("Expecting identical synthetic samples with the same" +
+			" seed")
+	}
+}
+
+func TestInitGlobalRand(t *testing.T) {
+	rand.Seed(1)
+	exp := rand.Int63()
+
+	rand.Seed(1)
+
+	smot := smote.New(PercentOver, K, 5)
+	smot.Init()
+
+	if smot.Rand == nil {
+		t.Fatal("Expecting local random generator, got nil")
+	}
+
+	got := rand.Int63()
+	if got != exp {
+		t.Fatalf("Expecting global random is not seeded, got %d"+
+			" instead of %d", got, exp)
+	}
+}
+
+func TestWriteResampled(t *testing.T) {
+	dataset := tabula.Claset{}
+
+	_, e := dsv.SimpleRead(fcfg, &dataset)
+	if nil != e {
+		t.Fatal(e)
+	}
+
+	smot := smote.New(PercentOver, K, 5)
+
+	e = smot.ResamplingRows(*dataset.GetMinorityRows())
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	file := "phoneme_resampled.csv"
+
+	e = smot.WriteResampled(file, &dataset)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	out, e := ioutil.ReadFile(file)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	_ = os.Remove(file)
+
+	exp := dataset.GetNRow() + smot.GetSynthetics().Len()
+	got := bytes.Count(out, []byte("\n"))
+
+	if exp != got {
+		t.Fatalf("Expecting %d rows, got %d", exp, got)
+	}
+}